@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var (
+	convertFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "from-format, f",
+			Usage: "Format of the input: json, logfmt, default, debug or a template. Defaults to detecting JSON, logfmt and the default format",
+		},
+		cli.StringFlag{
+			Name:  "to, t",
+			Value: "json",
+			Usage: "Format of the output: json, logfmt, default, debug or a template",
+		},
+	}
+	convertCmd = cli.Command{
+		Name:      "convert",
+		Usage:     "Converts the entries of log files or STDIN into another format",
+		ArgsUsage: "[files...]",
+		Flags:     convertFlags,
+		Action:    convertRun,
+	}
+)
+
+func convertRun(c *cli.Context) error {
+	dec, err := newDecoder(c.String("from-format"))
+	if err != nil {
+		return err
+	}
+	enc, err := newEncoder(c.String("to"))
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	var skipped int
+	err = forEachEntry(c.Args(), dec, func(e *entry) error {
+		if e.record == nil {
+			skipped += len(e.lines)
+			return nil
+		}
+		return writeEntry(out, e, enc)
+	})
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d lines that could not be decoded\n", skipped)
+	}
+	return err
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+var (
+	filterFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "level, l",
+			Usage: "Only entries at least as severe as the given log-level",
+		},
+		cli.StringSliceFlag{
+			Name:  "logger, n",
+			Usage: "Only entries of loggers matching the given glob, e.g. 'main.*' (repeatable)",
+		},
+		cli.StringFlag{
+			Name:  "since, s",
+			Usage: "Only entries logged at or after the given time, e.g. 2006-01-02T15:04:05 or 1h for an hour ago",
+		},
+		cli.StringFlag{
+			Name:  "until, u",
+			Usage: "Only entries logged before the given time",
+		},
+		cli.StringFlag{
+			Name:  "message, m",
+			Usage: "Only entries with messages matching the given regular expression",
+		},
+		cli.StringSliceFlag{
+			Name:  "field, F",
+			Usage: "Only entries matching the given predicate: key, key=value, key!=value, key~regexp or key!~regexp (repeatable)",
+		},
+		cli.StringFlag{
+			Name:  "from-format, from",
+			Usage: "Format of the input: json, logfmt, default, debug or a template. Defaults to detecting JSON, logfmt and the default format",
+		},
+		cli.StringFlag{
+			Name:  "to, t",
+			Usage: "Format of the output: json, logfmt, default, debug or a template. Defaults to the lines as read",
+		},
+	}
+	filterCmd = cli.Command{
+		Name:      "filter",
+		Usage:     "Writes the entries of log files or STDIN that match all given filters",
+		ArgsUsage: "[files...]",
+		Flags:     filterFlags,
+		Action:    filterRun,
+	}
+)
+
+func filterRun(c *cli.Context) error {
+	match, err := newFilter(c)
+	if err != nil {
+		return err
+	}
+	dec, err := newDecoder(c.String("from-format"))
+	if err != nil {
+		return err
+	}
+	var enc octolog.Encoder
+	if c.String("to") != "" {
+		if enc, err = newEncoder(c.String("to")); err != nil {
+			return err
+		}
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	return forEachEntry(c.Args(), dec, func(e *entry) error {
+		if e.record == nil || !match(e.record) {
+			return nil
+		}
+		return writeEntry(out, e, enc)
+	})
+}
+
+// writeEntry writes the given entry with the given encoder, or as read
+// without encoder.
+func writeEntry(out *bufio.Writer, e *entry, enc octolog.Encoder) error {
+	if enc == nil || e.record == nil {
+		for i := range e.lines {
+			if _, err := out.WriteString(e.lines[i] + "\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	_, err := out.WriteString(enc.Encode(e.record.Entry(), true) + "\n")
+	return err
+}
+
+// newFilter returns a function matching the records selected by the flags of
+// the given context.
+func newFilter(c *cli.Context) (func(r *octolog.Record) bool, error) {
+	var filters []func(r *octolog.Record) bool
+	if name := c.String("level"); name != "" {
+		threshold, err := level.Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %q", err, name)
+		}
+		filters = append(filters, func(r *octolog.Record) bool {
+			lvl, err := level.Parse(r.Level)
+			return err == nil && lvl <= threshold
+		})
+	}
+	if globs := c.StringSlice("logger"); len(globs) > 0 {
+		for i := range globs {
+			if _, err := path.Match(globs[i], ""); err != nil {
+				return nil, fmt.Errorf("%v: %q", err, globs[i])
+			}
+		}
+		filters = append(filters, func(r *octolog.Record) bool {
+			for i := range globs {
+				if ok, _ := path.Match(globs[i], r.Logger); ok {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	now := time.Now()
+	if s := c.String("since"); s != "" {
+		since, err := parseTimeFlag(s, now)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, func(r *octolog.Record) bool {
+			return !r.Time.IsZero() && !r.Time.Before(since)
+		})
+	}
+	if s := c.String("until"); s != "" {
+		until, err := parseTimeFlag(s, now)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, func(r *octolog.Record) bool {
+			return !r.Time.IsZero() && r.Time.Before(until)
+		})
+	}
+	if expr := c.String("message"); expr != "" {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, func(r *octolog.Record) bool {
+			return re.MatchString(r.Message)
+		})
+	}
+	for _, predicate := range c.StringSlice("field") {
+		filter, err := parsePredicate(predicate)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return func(r *octolog.Record) bool {
+		for i := range filters {
+			if !filters[i](r) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// timeLayouts are the layouts accepted by parseTimeFlag.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// parseTimeFlag returns the given time in the local time zone, or the time
+// the given duration before now.
+func parseTimeFlag(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for i := range timeLayouts {
+		if t, err := time.ParseInLocation(timeLayouts[i], s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %q", s)
+}
+
+// parsePredicate returns a filter for the given field predicate.
+func parsePredicate(predicate string) (func(r *octolog.Record) bool, error) {
+	i := strings.IndexAny(predicate, "=~")
+	if i < 0 {
+		return func(r *octolog.Record) bool {
+			_, ok := recordField(r, predicate)
+			return ok
+		}, nil
+	}
+	key, op, value := predicate[:i], predicate[i:i+1], predicate[i+1:]
+	negate := strings.HasSuffix(key, "!")
+	key = strings.TrimSuffix(key, "!")
+	if key == "" {
+		return nil, fmt.Errorf("invalid field predicate: %q", predicate)
+	}
+	matches := func(v string) bool { return v == value }
+	if op == "~" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		matches = re.MatchString
+	}
+	return func(r *octolog.Record) bool {
+		v, ok := recordField(r, key)
+		return ok && matches(v) != negate
+	}, nil
+}
+
+// recordField returns the value of the given key, where the keys written by
+// the structured encodings (e.g. level, logger, file) refer to the values of
+// the given record and any other key to its fields.
+func recordField(r *octolog.Record, key string) (string, bool) {
+	switch key {
+	case "time":
+		return r.Time.Format(time.RFC3339Nano), !r.Time.IsZero()
+	case "level":
+		return r.Level, true
+	case "logger":
+		return r.Logger, true
+	case "message":
+		return r.Message, true
+	case "gid":
+		return strconv.FormatUint(r.GID, 10), true
+	case "lid":
+		return strconv.FormatUint(r.LID, 10), true
+	case "pid":
+		return strconv.Itoa(r.PID), true
+	case "func":
+		return r.Func, r.Func != ""
+	case "file":
+		return r.File, r.File != ""
+	case "line":
+		return strconv.Itoa(r.Line), r.Line != 0
+	case "error":
+		if len(r.ErrorChain) == 0 {
+			return "", false
+		}
+		return r.ErrorChain[0].Message, true
+	}
+	v, ok := r.Fields[key]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%v", v), true
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+func TestParsePredicate(t *testing.T) {
+	r := &octolog.Record{
+		Level:   "INFO",
+		Logger:  "main.db",
+		Message: "query took 3ms",
+		Fields:  map[string]interface{}{"user": "bob", "attempt": 3},
+	}
+	for _, test := range []struct {
+		predicate string
+		matches   bool
+	}{
+		{"user", true},
+		{"session", false},
+		{"user=bob", true},
+		{"user=alice", false},
+		{"attempt=3", true},
+		{"user!=alice", true},
+		{"user!=bob", false},
+		{"session!=bob", false},
+		{"user~^b", true},
+		{"user~^a", false},
+		{"user!~^a", true},
+		{"user!~^b", false},
+		{"level=INFO", true},
+		{"logger~^main\\.", true},
+		{"message~\\d+ms$", true},
+		{"func", false},
+	} {
+		filter, err := parsePredicate(test.predicate)
+		if err != nil {
+			t.Errorf("%q: %s", test.predicate, err)
+			continue
+		}
+		if got := filter(r); got != test.matches {
+			t.Errorf("%q: expected %v, got %v", test.predicate, test.matches, got)
+		}
+	}
+	for _, predicate := range []string{"=bob", "!=bob", "user~("} {
+		if _, err := parsePredicate(predicate); err == nil {
+			t.Errorf("%q: expected an error", predicate)
+		}
+	}
+}
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2019, 10, 10, 10, 10, 10, 0, time.Local)
+	for _, test := range []struct {
+		value    string
+		expected time.Time
+	}{
+		{"1h", now.Add(-time.Hour)},
+		{"90s", now.Add(-90 * time.Second)},
+		{"2019-10-09T08:07:06", time.Date(2019, 10, 9, 8, 7, 6, 0, time.Local)},
+		{"2019-10-09 08:07:06", time.Date(2019, 10, 9, 8, 7, 6, 0, time.Local)},
+		{"2019/10/09 08:07:06", time.Date(2019, 10, 9, 8, 7, 6, 0, time.Local)},
+		{"2019-10-09", time.Date(2019, 10, 9, 0, 0, 0, 0, time.Local)},
+		{"2019/10/09", time.Date(2019, 10, 9, 0, 0, 0, 0, time.Local)},
+		{"2019-10-09T08:07:06.5Z", time.Date(2019, 10, 9, 8, 7, 6, 500000000, time.UTC)},
+	} {
+		got, err := parseTimeFlag(test.value, now)
+		if err != nil {
+			t.Errorf("%q: %s", test.value, err)
+			continue
+		}
+		if !got.Equal(test.expected) {
+			t.Errorf("%q: expected %s, got %s", test.value, test.expected, got)
+		}
+	}
+	for _, value := range []string{"yesterday", "10/09/2019"} {
+		if _, err := parseTimeFlag(value, now); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2019, 10, 10, 10, minute, 0, 0, time.Local)
+	}
+	records := []*octolog.Record{
+		{Time: at(1), Level: "DEBUG", Logger: "main", Message: "starting"},
+		{Time: at(2), Level: "WARNING", Logger: "main.db", Message: "slow query"},
+		{Time: at(3), Level: "ERROR", Logger: "main.http", Message: "request failed"},
+		{Time: at(4), Level: "AUDIT", Logger: "audit", Message: "password changed"},
+		{Level: "ERROR", Logger: "main.db", Message: "no time"},
+	}
+	for _, test := range []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "starting,slow query,request failed,password changed,no time"},
+		{[]string{"--level", "WARNING"}, "slow query,request failed,no time"},
+		{[]string{"--level", "error"}, "request failed,no time"},
+		{[]string{"--logger", "main.*"}, "slow query,request failed,no time"},
+		{[]string{"--logger", "main", "--logger", "audit"}, "starting,password changed"},
+		{[]string{"--since", "2019-10-10 10:02:00", "--until", "2019-10-10 10:04:00"}, "slow query,request failed"},
+		{[]string{"--message", "^(slow|no) "}, "slow query,no time"},
+		{[]string{"--level", "ERROR", "--logger", "main.db"}, "no time"},
+	} {
+		match, err := newFilter(filterContext(t, test.args))
+		if err != nil {
+			t.Errorf("%q: %s", test.args, err)
+			continue
+		}
+		var matched []string
+		for _, r := range records {
+			if match(r) {
+				matched = append(matched, r.Message)
+			}
+		}
+		if got := strings.Join(matched, ","); got != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.args, test.expected, got)
+		}
+	}
+	for _, args := range [][]string{
+		{"--level", "WARN"},
+		{"--logger", "[main"},
+		{"--since", "yesterday"},
+		{"--message", "("},
+		{"--field", "=x"},
+	} {
+		if _, err := newFilter(filterContext(t, args)); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestWriteEntry(t *testing.T) {
+	dec, err := newDecoder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := `{"time":"2019-10-10T10:10:10Z","level":"INFO","logger":"main","message":"hello \"world\""}`
+	logfmt, err := newEncoder("logfmt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := newEncoder("{{.Level}} {{.Message}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		enc      octolog.Encoder
+		expected string
+	}{
+		{nil, line + "\n"},
+		{logfmt, `time=2019-10-10T10:10:10Z level=INFO logger=main message="hello \"world\"" gid=0 lid=0 pid=0` + "\n"},
+		{text, `INFO hello "world"` + "\n"},
+	} {
+		r, err := dec.Decode(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := new(bytes.Buffer)
+		out := bufio.NewWriter(buf)
+		if err := writeEntry(out, &entry{record: r, lines: []string{line}}, test.enc); err != nil {
+			t.Fatal(err)
+		}
+		out.Flush()
+		if got := buf.String(); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
+
+// filterContext returns the context of octolog filter with the given
+// arguments.
+func filterContext(t *testing.T, args []string) *cli.Context {
+	set := flag.NewFlagSet("filter", flag.ContinueOnError)
+	for i := range filterFlags {
+		filterFlags[i].Apply(set)
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(nil, set, nil)
+}
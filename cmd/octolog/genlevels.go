@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/octogo/log/pkg/config"
+	"github.com/urfave/cli"
+)
+
+var (
+	genlevelsFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "from, f",
+			Value: "logging.yml",
+			Usage: "Configuration file to read the custom log-levels from",
+		},
+		cli.StringFlag{
+			Name:  "pkg, p",
+			Value: "levels",
+			Usage: "Name of the Go package to generate",
+		},
+		cli.StringFlag{
+			Name:  "dir, d",
+			Usage: "Directory to write levels.go to. Defaults to the name of the package",
+		},
+		cli.BoolFlag{
+			Name:  "stdout, o",
+			Usage: "Print the source file to STDOUT rather than writing it to disk",
+		},
+	}
+	genlevelsCmd = cli.Command{
+		Name:   "gen-levels",
+		Usage:  "Creates a Go package with variables and Logger methods for the custom log-levels of a configuration file",
+		Flags:  genlevelsFlags,
+		Action: genlevelsRun,
+	}
+)
+
+// generatedPrefix starts the source files generated by gen-levels, which
+// are overwritten by later runs.
+const generatedPrefix = "// Code generated by octolog gen-levels"
+
+func genlevelsRun(c *cli.Context) error {
+	from := c.String("from")
+	if problems := validateFile(from); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	cfg, err := config.ReadRaw(from)
+	if err != nil {
+		return err
+	}
+	src, err := config.GetLevelsSource(c.String("pkg"), filepath.Base(from), cfg.Levels)
+	if err != nil {
+		return err
+	}
+	if c.Bool("stdout") {
+		fmt.Print(src)
+		return nil
+	}
+
+	dir := c.String("dir")
+	if dir == "" {
+		dir = c.String("pkg")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	srcFile := filepath.Join(dir, "levels.go")
+	if existing, err := ioutil.ReadFile(srcFile); err == nil && !strings.HasPrefix(string(existing), generatedPrefix) {
+		return fmt.Errorf("aborting because file already exists and has not been generated: %s", srcFile)
+	}
+	if err := ioutil.WriteFile(srcFile, []byte(src), 0644); err != nil {
+		return err
+	}
+	log.Printf("created: %s\n", srcFile)
+	return nil
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/octogo/log/pkg/config"
 	"github.com/urfave/cli"
@@ -23,18 +26,30 @@ var (
 			Name:  "stdout, o",
 			Usage: "Print sample source file to STDOUT rather than writing it to disk",
 		},
+		cli.StringFlag{
+			Name:  "from, f",
+			Usage: "Configuration file to build the config.Config literal from, rather than the sample",
+		},
 	}
 	gensrcCmd = cli.Command{
 		Name:   "gensrc",
-		Usage:  "Creates a Go source file with sample code in your CWD",
+		Usage:  "Creates a Go source file with sample code, or the code building a configuration, in your CWD",
 		Flags:  gensrcFlags,
 		Action: gensrcRun,
 	}
 )
 
 func gensrcRun(c *cli.Context) error {
+	src := config.GetSampleSource(c.String("pkg"))
+	if from := c.String("from"); from != "" {
+		var err error
+		if src, err = configSource(c.String("pkg"), from); err != nil {
+			return err
+		}
+	}
 	if c.Bool("stdout") {
-		fmt.Println(config.GetSampleSource(c.String("pkg")))
+		fmt.Print(src)
+		return nil
 	}
 
 	srcFile := c.String("name")
@@ -49,7 +64,23 @@ func gensrcRun(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	config.WriteSampleSource(c.String("pkg"), f)
+	defer f.Close()
+	if _, err := f.WriteString(src); err != nil {
+		return err
+	}
 	log.Printf("created: %s\n", srcFile)
 	return nil
 }
+
+// configSource returns the Go source file building the configuration of the
+// given file, which must be valid.
+func configSource(pkg, file string) (string, error) {
+	if problems := validateFile(file); len(problems) > 0 {
+		return "", errors.New(strings.Join(problems, "\n"))
+	}
+	cfg, err := config.ReadRaw(file)
+	if err != nil {
+		return "", err
+	}
+	return config.GetSource(pkg, filepath.Base(file), cfg)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/octogo/log/pkg/parse"
+)
+
+// newDecoder returns the decoder for the given format: json, logfmt, default
+// or debug (the default text formats) or a template. Without a format, JSON
+// and logfmt are detected per line, falling back to the default log-format.
+func newDecoder(format string) (octolog.Decoder, error) {
+	switch strings.ToLower(format) {
+	case "":
+		text, err := parse.Compile(octolog.DefaultLogFormat)
+		if err != nil {
+			return nil, err
+		}
+		return fallbackDecoder{octolog.NewDecoder(""), text}, nil
+	case octolog.JSONFormat, octolog.LogfmtFormat:
+		return octolog.NewDecoder(format), nil
+	case "default":
+		return parse.Compile(octolog.DefaultLogFormat)
+	case "debug":
+		return parse.Compile(octolog.DefaultDebugFormat)
+	default:
+		return parse.Compile(format)
+	}
+}
+
+// fallbackDecoder tries its decoders in order.
+type fallbackDecoder []octolog.Decoder
+
+func (decoders fallbackDecoder) Decode(line string) (r *octolog.Record, err error) {
+	for i := range decoders {
+		if r, err = decoders[i].Decode(line); err == nil {
+			return r, nil
+		}
+	}
+	return nil, err
+}
+
+// newEncoder returns the encoder for the given format: json, logfmt, default
+// or debug (the default text formats) or a template.
+func newEncoder(format string) (octolog.Encoder, error) {
+	switch strings.ToLower(format) {
+	case "default":
+		format = octolog.DefaultLogFormat
+	case "debug":
+		format = octolog.DefaultDebugFormat
+	}
+	if err := octolog.ValidateFormat(format); err != nil {
+		return nil, err
+	}
+	return octolog.NewEncoder(format), nil
+}
+
+// entry is a decoded entry along with the lines it has been decoded from.
+// Lines that could not be decoded are entries without record.
+type entry struct {
+	record *octolog.Record
+	lines  []string
+}
+
+// entryReader reads entries line by line. Lines following an entry that are
+// indented and can not be decoded, such as stack traces, belong to the entry.
+type entryReader struct {
+	r    *bufio.Reader
+	dec  octolog.Decoder
+	next *entry
+	err  error
+}
+
+func newEntryReader(r io.Reader, dec octolog.Decoder) *entryReader {
+	return &entryReader{r: bufio.NewReader(r), dec: dec}
+}
+
+// Next returns the next entry or io.EOF.
+func (er *entryReader) Next() (*entry, error) {
+	current := er.next
+	er.next = nil
+	for er.err == nil {
+		line, err := er.r.ReadString('\n')
+		if err != nil {
+			er.err = err
+			if line == "" {
+				break
+			}
+		}
+		line = strings.TrimRight(line, "\r\n")
+		r, decodeErr := er.dec.Decode(line)
+		if decodeErr != nil && current != nil && current.record != nil &&
+			(strings.HasPrefix(line, "\t") || strings.HasPrefix(line, " ")) {
+			current.lines = append(current.lines, line)
+			continue
+		}
+		e := &entry{record: r, lines: []string{line}}
+		if current == nil {
+			current = e
+			continue
+		}
+		er.next = e
+		return current.decodeDetails(), nil
+	}
+	if current != nil {
+		return current.decodeDetails(), nil
+	}
+	return nil, er.err
+}
+
+// decodeDetails restores the details written on the lines following the
+// first line of this entry into its record.
+func (e *entry) decodeDetails() *entry {
+	if e.record != nil && len(e.lines) > 1 {
+		parse.DecodeDetails(e.record, e.lines[1:])
+	}
+	return e
+}
+
+// forEachEntry calls fn with every entry of the given files, or of STDIN if
+// no files or "-" are given.
+func forEachEntry(files []string, dec octolog.Decoder, fn func(e *entry) error) error {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for i := range files {
+		err := withInput(files[i], func(r io.Reader) error {
+			er := newEntryReader(r, dec)
+			for {
+				e, err := er.Next()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if err := fn(e); err != nil {
+					return err
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachLine calls fn with every line of the given files, or of STDIN if no
+// files or "-" are given. Line endings are stripped.
+func forEachLine(files []string, fn func(line string) error) error {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for i := range files {
+		err := withInput(files[i], func(in io.Reader) error {
+			r := bufio.NewReader(in)
+			for {
+				line, err := r.ReadString('\n')
+				if line != "" {
+					if err := fn(strings.TrimRight(line, "\r\n")); err != nil {
+						return err
+					}
+				}
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withInput calls fn with the given file, or STDIN for "-".
+func withInput(file string, fn func(r io.Reader) error) error {
+	r, err := openInput(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return fn(r)
+}
+
+// openInput opens the given file, or STDIN for "-", decompressing it if it
+// is gzip-compressed.
+func openInput(file string) (io.ReadCloser, error) {
+	var f io.ReadCloser = ioutil.NopCloser(os.Stdin)
+	if file != "-" {
+		var err error
+		if f, err = os.Open(file); err != nil {
+			return nil, err
+		}
+	}
+	buf := bufio.NewReader(f)
+	if magic, _ := buf.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buf)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return readCloser{gz, f}, nil
+	}
+	return readCloser{buf, f}, nil
+}
+
+// readCloser reads from its Reader and closes its Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+var (
+	mergeFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "from-format, f",
+			Usage: "Format of the input: json, logfmt, default, debug or a template. Defaults to detecting JSON, logfmt and the default format",
+		},
+		cli.StringFlag{
+			Name:  "to, t",
+			Usage: "Format of the output: json, logfmt, default, debug or a template. Defaults to the lines as read",
+		},
+		cli.BoolFlag{
+			Name:  "rotated, r",
+			Usage: "Include the rotated segments of every file, e.g. app.log.2.gz and app.log.1 before app.log",
+		},
+		cli.StringFlag{
+			Name:  "source-key, k",
+			Value: "source",
+			Usage: "Field to tag encoded entries with their source. Lines as read are prefixed with it",
+		},
+		cli.BoolFlag{
+			Name:  "no-source",
+			Usage: "Do not tag entries with their source",
+		},
+	}
+	mergeCmd = cli.Command{
+		Name:      "merge",
+		Usage:     "Interleaves the entries of log files chronologically",
+		ArgsUsage: "files...",
+		Flags:     mergeFlags,
+		Action:    mergeRun,
+	}
+)
+
+func mergeRun(c *cli.Context) error {
+	if len(c.Args()) == 0 {
+		return errors.New("no files to merge")
+	}
+	dec, err := newDecoder(c.String("from-format"))
+	if err != nil {
+		return err
+	}
+	var enc octolog.Encoder
+	if c.String("to") != "" {
+		if enc, err = newEncoder(c.String("to")); err != nil {
+			return err
+		}
+	}
+	sourceKey := c.String("source-key")
+	if c.Bool("no-source") {
+		sourceKey = ""
+	}
+	return merge(os.Stdout, c.Args(), c.Bool("rotated"), dec, enc, sourceKey)
+}
+
+// merge writes the entries of the given files, and of their rotated segments
+// if requested, to the given writer in chronological order.
+func merge(w io.Writer, files []string, rotated bool, dec octolog.Decoder, enc octolog.Encoder, sourceKey string) error {
+	var sources mergeHeap
+	defer func() {
+		for i := range sources {
+			sources[i].Close()
+		}
+	}()
+	for i, file := range files {
+		segments := []string{file}
+		if rotated {
+			segments = rotatedSegments(file)
+		}
+		src := &mergeSource{
+			name:   file,
+			index:  i,
+			reader: &segmentReader{segments: segments},
+		}
+		src.entries = newEntryReader(src.reader, dec)
+		if err := src.advance(); err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		sources = append(sources, src)
+	}
+	heap.Init(&sources)
+
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+	for sources.Len() > 0 {
+		src := sources[0]
+		if err := writeMerged(out, src, enc, sourceKey); err != nil {
+			return err
+		}
+		if err := src.advance(); err == io.EOF {
+			heap.Pop(&sources)
+			src.Close()
+		} else if err != nil {
+			return err
+		} else {
+			heap.Fix(&sources, 0)
+		}
+	}
+	return nil
+}
+
+// writeMerged writes the current entry of the given source, tagged with the
+// name of the source under the given key.
+func writeMerged(out *bufio.Writer, src *mergeSource, enc octolog.Encoder, sourceKey string) error {
+	e := src.head
+	if sourceKey == "" {
+		return writeEntry(out, e, enc)
+	}
+	if enc == nil || e.record == nil {
+		for i := range e.lines {
+			if _, err := out.WriteString(src.name + ": " + e.lines[i] + "\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	fields := make(map[string]interface{}, len(e.record.Fields)+1)
+	for k, v := range e.record.Fields {
+		fields[k] = v
+	}
+	fields[sourceKey] = src.name
+	e.record.Fields = fields
+	return writeEntry(out, e, enc)
+}
+
+// mergeSource is a file, including its rotated segments, to be merged.
+type mergeSource struct {
+	name    string
+	index   int
+	reader  *segmentReader
+	entries *entryReader
+	head    *entry
+	time    time.Time
+}
+
+// advance reads the next entry of this source. Entries without timestamp
+// keep the time of their predecessor, so they stay in place.
+func (src *mergeSource) advance() error {
+	e, err := src.entries.Next()
+	if err != nil {
+		return err
+	}
+	src.head = e
+	if e.record != nil && !e.record.Time.IsZero() {
+		src.time = e.record.Time
+	}
+	return nil
+}
+
+func (src *mergeSource) Close() error {
+	return src.reader.Close()
+}
+
+// mergeHeap orders sources by the time of their current entries, breaking
+// ties by GID and LID and finally by the order of the sources.
+type mergeHeap []*mergeSource
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	if !a.time.Equal(b.time) {
+		return a.time.Before(b.time)
+	}
+	if ra, rb := a.head.record, b.head.record; ra != nil && rb != nil {
+		if ra.GID != rb.GID {
+			return ra.GID < rb.GID
+		}
+		if ra.LID != rb.LID {
+			return ra.LID < rb.LID
+		}
+	}
+	return a.index < b.index
+}
+
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x interface{}) { *h = append(*h, x.(*mergeSource)) }
+
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	src := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return src
+}
+
+// segmentReader reads the given files one after another, terminating the
+// last line of every file.
+type segmentReader struct {
+	segments []string
+	current  io.ReadCloser
+	last     byte
+}
+
+func (s *segmentReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if s.current == nil {
+			if s.last != 0 && s.last != '\n' {
+				p[0], s.last = '\n', '\n'
+				return 1, nil
+			}
+			if len(s.segments) == 0 {
+				return 0, io.EOF
+			}
+			r, err := openInput(s.segments[0])
+			if err != nil {
+				return 0, err
+			}
+			s.current, s.segments = r, s.segments[1:]
+		}
+		n, err := s.current.Read(p)
+		if n > 0 {
+			s.last = p[n-1]
+		}
+		if err == io.EOF {
+			s.current.Close()
+			s.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (s *segmentReader) Close() error {
+	s.segments = nil
+	if s.current == nil {
+		return nil
+	}
+	err := s.current.Close()
+	s.current = nil
+	return err
+}
+
+// rotatedSegments returns the rotated segments of the given file, from the
+// oldest to the file itself, e.g. app.log.2.gz, app.log.1, app.log.
+func rotatedSegments(file string) []string {
+	matches, _ := filepath.Glob(file + ".*")
+	numbers := map[string]int{}
+	var segments []string
+	for _, match := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(match, file+"."), ".gz")
+		if n, err := strconv.Atoi(suffix); err == nil && n >= 0 {
+			numbers[match] = n
+			segments = append(segments, match)
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool { return numbers[segments[i]] > numbers[segments[j]] })
+	if _, err := os.Stat(file); err == nil || len(segments) == 0 {
+		segments = append(segments, file)
+	}
+	return segments
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	octolog "github.com/octogo/log/pkg/log"
+)
+
+func TestMerge(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		files    map[string]string
+		merge    []string
+		rotated  bool
+		expected string
+	}{
+		{
+			name: "by time",
+			files: map[string]string{
+				"a.log": "level=INFO time=2019-10-10T10:10:10Z message=a1\nlevel=INFO time=2019-10-10T10:10:30Z message=a2\n",
+				"b.log": "level=INFO time=2019-10-10T10:10:20Z message=b1\nlevel=INFO time=2019-10-10T10:10:40Z message=b2",
+			},
+			merge:    []string{"a.log", "b.log"},
+			expected: "a1 b1 a2 b2",
+		},
+		{
+			name: "ties by GID, LID and source",
+			files: map[string]string{
+				"a.log": "level=INFO time=2019-10-10T10:10:10Z gid=2 lid=1 message=a1\nlevel=INFO time=2019-10-10T10:10:10Z gid=3 lid=1 message=a2\nlevel=INFO time=2019-10-10T10:10:20Z message=a3\n",
+				"b.log": "level=INFO time=2019-10-10T10:10:10Z gid=2 lid=0 message=b1\nlevel=INFO time=2019-10-10T10:10:20Z message=b2\n",
+			},
+			merge:    []string{"a.log", "b.log"},
+			expected: "b1 a1 a2 a3 b2",
+		},
+		{
+			name: "undecoded lines stay in place",
+			files: map[string]string{
+				"a.log": "level=INFO time=2019-10-10T10:10:10Z message=a1\nnot an entry\nlevel=INFO time=2019-10-10T10:10:30Z message=a2\n",
+				"b.log": "level=INFO time=2019-10-10T10:10:20Z message=b1\n",
+			},
+			merge:    []string{"a.log", "b.log"},
+			expected: "a1 not-an-entry b1 a2",
+		},
+		{
+			name: "rotated segments",
+			files: map[string]string{
+				"a.log":      "level=INFO time=2019-10-10T10:10:50Z message=a4\n",
+				"a.log.1":    "level=INFO time=2019-10-10T10:10:30Z message=a3\n",
+				"a.log.2.gz": "level=INFO time=2019-10-10T10:10:10Z message=a1\nlevel=INFO time=2019-10-10T10:10:20Z message=a2",
+				"b.log":      "level=INFO time=2019-10-10T10:10:25Z message=b1\nlevel=INFO time=2019-10-10T10:10:40Z message=b2\n",
+				"b.log.old":  "level=INFO time=2019-10-10T10:10:00Z message=b0\n",
+			},
+			merge:    []string{"a.log", "b.log"},
+			rotated:  true,
+			expected: "a1 a2 b1 a3 b2 a4",
+		},
+	} {
+		dir, err := ioutil.TempDir("", "octolog-merge")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		for name, content := range test.files {
+			data := []byte(content)
+			if strings.HasSuffix(name, ".gz") {
+				buf := new(bytes.Buffer)
+				zw := gzip.NewWriter(buf)
+				zw.Write(data)
+				zw.Close()
+				data = buf.Bytes()
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		files := make([]string, len(test.merge))
+		for i := range files {
+			files[i] = filepath.Join(dir, test.merge[i])
+		}
+		dec, err := newDecoder("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := new(bytes.Buffer)
+		if err := merge(out, files, test.rotated, dec, nil, ""); err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		var messages []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if i := strings.Index(line, "message="); i >= 0 {
+				line = line[i+len("message="):]
+			}
+			messages = append(messages, strings.Replace(line, " ", "-", -1))
+		}
+		if got := strings.Join(messages, " "); got != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestMergeSourceKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "octolog-merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "a.log")
+	if err := ioutil.WriteFile(file, []byte("level=INFO time=2019-10-10T10:10:10Z message=a1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dec, _ := newDecoder("")
+	enc, _ := newEncoder("logfmt")
+	for _, test := range []struct {
+		enc      octolog.Encoder
+		expected string
+	}{
+		{nil, file + ": level=INFO time=2019-10-10T10:10:10Z message=a1\n"},
+		{enc, "source=" + file},
+	} {
+		out := new(bytes.Buffer)
+		if err := merge(out, []string{file}, false, dec, test.enc, "source"); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), test.expected) {
+			t.Errorf("expected %q in %q", test.expected, out.String())
+		}
+	}
+}
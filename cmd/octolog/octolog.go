@@ -19,6 +19,17 @@ func main() {
 	app.Commands = []cli.Command{
 		genconfCmd,
 		gensrcCmd,
+		genlevelsCmd,
+		validateCmd,
+		schemaCmd,
+		prettyCmd,
+		filterCmd,
+		convertCmd,
+		statsCmd,
+		mergeCmd,
+		sendCmd,
+		wrapCmd,
+		previewCmd,
 	}
 
 	err := app.Run(os.Args)
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"os"
+
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/octogo/log/pkg/log/terminal"
+	"github.com/urfave/cli"
+)
+
+var (
+	prettyFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Log-format to render entries with. Defaults to the default log-format",
+		},
+		cli.BoolFlag{
+			Name:  "color, c",
+			Usage: "Colorize entries even if STDOUT is not a terminal",
+		},
+		cli.BoolFlag{
+			Name:  "no-color, n",
+			Usage: "Never colorize entries",
+		},
+	}
+	prettyCmd = cli.Command{
+		Name:      "pretty",
+		Usage:     "Renders JSON or logfmt entries from files or STDIN in a log-format",
+		ArgsUsage: "[files...]",
+		Flags:     prettyFlags,
+		Action:    prettyRun,
+	}
+)
+
+func prettyRun(c *cli.Context) error {
+	format := c.String("format")
+	if format == "" {
+		format = octolog.DefaultLogFormat
+	}
+	if err := octolog.ValidateFormat(format); err != nil {
+		return err
+	}
+	enc := octolog.NewEncoder(format)
+	dec := octolog.NewDecoder("")
+	disableColors := !terminal.IsTerminal(int(os.Stdout.Fd()))
+	if c.Bool("color") {
+		disableColors = false
+	}
+	if c.Bool("no-color") {
+		disableColors = true
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	return forEachLine(c.Args(), func(line string) error {
+		if r, err := dec.Decode(line); err == nil {
+			line = enc.Encode(r.Entry(), disableColors)
+		}
+		_, err := out.WriteString(line + "\n")
+		return err
+	})
+}
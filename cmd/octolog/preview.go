@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octogo/log/pkg/color"
+	"github.com/octogo/log/pkg/config"
+	"github.com/octogo/log/pkg/level"
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+var (
+	previewFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Log-format to preview: a template, json or logfmt. Defaults to the configured default format",
+		},
+		cli.StringFlag{
+			Name:  "config, c",
+			Value: "logging.yml",
+			Usage: "Configuration file to load levels and the default format from, if it exists",
+		},
+	}
+	previewCmd = cli.Command{
+		Name:   "preview",
+		Usage:  "Renders sample entries of every log-level in a log-format, with and without colors",
+		Flags:  previewFlags,
+		Action: previewRun,
+	}
+)
+
+func previewRun(c *cli.Context) error {
+	defaultFormat, err := previewConfig(c.String("config"), c.IsSet("config"))
+	if err != nil {
+		return err
+	}
+	format := c.String("format")
+	if format == "" {
+		format = defaultFormat
+	}
+	if err := octolog.ValidateFormat(format); err != nil {
+		return cli.NewExitError(formatError(format, err), 1)
+	}
+
+	levels := level.Levels()
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	modes := []struct {
+		title         string
+		disableColors bool
+	}{
+		{"color", false},
+		{"no color", true},
+	}
+	if format == octolog.JSONFormat || format == octolog.LogfmtFormat {
+		modes = modes[1:]
+	}
+	enc := octolog.NewEncoder(format)
+	now := time.Now()
+	sections := make([][]string, len(modes))
+	for i := range modes {
+		for j, lvl := range levels {
+			line := enc.Encode(sampleRecord(lvl, j, now).Entry(), modes[i].disableColors)
+			sections[i] = append(sections[i], line)
+		}
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	for i := range modes {
+		fmt.Fprintf(out, "%s:\n%s\n\n", modes[i].title, strings.Join(sections[i], "\n"))
+	}
+	fmt.Fprintln(out, "levels:")
+	for _, lvl := range levels {
+		fmt.Fprintf(out, "%s%-10s%s %q\n", lvl.Color(), lvl, "\x1b[0m", lvl.Color().String())
+	}
+	return nil
+}
+
+// previewConfig registers the log-levels of the given configuration file and
+// returns its default format. Unlike loading it, this does not open any
+// outputs. Unless required, a missing file implies the default log-format.
+func previewConfig(file string, required bool) (string, error) {
+	if _, err := os.Stat(file); err != nil {
+		if required || !os.IsNotExist(err) {
+			return "", err
+		}
+		return octolog.DefaultLogFormat, nil
+	}
+	cfg, err := config.ReadRaw(file)
+	if err != nil {
+		return "", err
+	}
+	for _, lvl := range cfg.Levels {
+		seq, err := color.Parse(lvl.Color)
+		if err != nil {
+			seq = color.NewLiteral(lvl.Color)
+		}
+		level.Register(lvl.Name, seq)
+	}
+	return cfg.DefaultFormat, nil
+}
+
+// sampleRecord returns the i-th sample entry with the given log-level, logged
+// a little after the given time.
+func sampleRecord(lvl level.Level, i int, now time.Time) *octolog.Record {
+	return &octolog.Record{
+		Time:    now.Add(time.Duration(i) * 1234567 * time.Microsecond),
+		Level:   lvl.String(),
+		Logger:  octolog.LoggerName,
+		Message: fmt.Sprintf("sample message logged with %s", lvl),
+		GID:     uint64(i + 1),
+		LID:     uint64(i + 1),
+		PID:     os.Getpid(),
+		PPID:    os.Getppid(),
+		Func:    "main.main",
+		File:    "main.go",
+		Line:    42,
+	}
+}
+
+// templateError matches the position and message of template errors, e.g.
+// template: octolog/entry:1:14: executing "octolog/entry" at <.Foo>: ...
+var templateError = regexp.MustCompile(`^template: [^:]*:(\d+)(?::(\d+))?: (?:executing "[^"]*" )?(.*)$`)
+
+// formatError describes the given error of the given log-format along with
+// its position, pointing at its column if known.
+func formatError(format string, err error) string {
+	match := templateError.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err.Error()
+	}
+	line, _ := strconv.Atoi(match[1])
+	msg := strings.Replace(match[3], "*log.entryStruct", "Entry", -1)
+	lines := strings.Split(format, "\n")
+	if line < 1 || line > len(lines) {
+		return fmt.Sprintf("line %d: %s", line, msg)
+	}
+	if match[2] == "" {
+		return fmt.Sprintf("line %d: %s\n\t%s", line, msg, lines[line-1])
+	}
+	offset, _ := strconv.Atoi(match[2])
+	marker := strings.Repeat(" ", offset) + "^"
+	return fmt.Sprintf("line %d, column %d: %s\n\t%s\n\t%s", line, offset+1, msg, lines[line-1], marker)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/octogo/log/pkg/level"
+	octolog "github.com/octogo/log/pkg/log"
+)
+
+func TestFormatError(t *testing.T) {
+	for _, test := range []struct {
+		format   string
+		expected string
+	}{
+		{
+			"{{.Date}} {{.Foo}}",
+			"line 1, column 13: at <.Foo>: can't evaluate field Foo in type Entry\n\t{{.Date}} {{.Foo}}\n\t            ^",
+		},
+		{
+			"{{.Date}}\n{{.Message",
+			"line 2: unclosed action\n\t{{.Message",
+		},
+		{
+			"{{.Message | foo}}",
+			"line 1: function \"foo\" not defined\n\t{{.Message | foo}}",
+		},
+	} {
+		err := octolog.ValidateFormat(test.format)
+		if err == nil {
+			t.Fatalf("%q: expected an error", test.format)
+		}
+		if got := formatError(test.format, err); got != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.format, test.expected, got)
+		}
+	}
+}
+
+func TestTemplateError(t *testing.T) {
+	for _, test := range []struct {
+		err      string
+		expected []string
+	}{
+		{
+			`template: octolog/entry:1:12: executing "octolog/entry" at <.Foo>: can't evaluate field Foo`,
+			[]string{"1", "12", "at <.Foo>: can't evaluate field Foo"},
+		},
+		{"template: octolog/entry:2: unclosed action", []string{"2", "", "unclosed action"}},
+		{"disk full", nil},
+	} {
+		match := templateError.FindStringSubmatch(test.err)
+		if test.expected == nil {
+			if match != nil {
+				t.Errorf("%q: expected no match, got %q", test.err, match)
+			}
+			continue
+		}
+		if len(match) != 4 || match[1] != test.expected[0] || match[2] != test.expected[1] || match[3] != test.expected[2] {
+			t.Errorf("%q: expected %q, got %q", test.err, test.expected, match)
+		}
+	}
+	if got := formatError("{{.Date}}", errors.New("template: octolog/entry:5: boom")); got != "line 5: boom" {
+		t.Errorf("expected %q, got %q", "line 5: boom", got)
+	}
+}
+
+func TestPreviewConfig(t *testing.T) {
+	defer level.Reset()
+	dir, err := ioutil.TempDir("", "octolog-preview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "app.log")
+	configFile := filepath.Join(dir, "logging.yml")
+	config := "defaultformat: '{{.Level}} {{.Message}}'\n" +
+		"defaultoutputs:\n  - file://" + logFile + "\n" +
+		"levels:\n  - name: AUDIT\n    color: bright-cyan\n"
+	if err := ioutil.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	format, err := previewConfig(configFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "{{.Level}} {{.Message}}" {
+		t.Errorf("expected %q, got %q", "{{.Level}} {{.Message}}", format)
+	}
+	if _, err := level.Parse("AUDIT"); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("expected %s not to be opened", logFile)
+	}
+
+	if format, err := previewConfig(filepath.Join(dir, "missing.yml"), false); err != nil || format != octolog.DefaultLogFormat {
+		t.Errorf("expected %q, got %q and %v", octolog.DefaultLogFormat, format, err)
+	}
+	if _, err := previewConfig(filepath.Join(dir, "missing.yml"), true); err == nil {
+		t.Error("expected an error for a missing required file")
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/octogo/log/pkg/config"
+	"github.com/urfave/cli"
+)
+
+var (
+	schemaCmd = cli.Command{
+		Name:   "schema",
+		Usage:  "Prints the JSON Schema of configuration files",
+		Action: schemaRun,
+	}
+)
+
+func schemaRun(c *cli.Context) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(config.Schema())
+}
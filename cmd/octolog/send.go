@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/octogo/log/pkg/config"
+	"github.com/octogo/log/pkg/level"
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+var (
+	sendFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "config, c",
+			Value: "logging.yml",
+			Usage: "Configuration file to load. Defaults are used if logging.yml does not exist",
+		},
+		cli.StringFlag{
+			Name:  "logger, n",
+			Usage: "Name of the logger to log with. Defaults to the configured root logger",
+		},
+		cli.StringFlag{
+			Name:  "level, l",
+			Value: "INFO",
+			Usage: "Log-level to log with",
+		},
+		cli.BoolFlag{
+			Name:  "stdin, s",
+			Usage: "Log every line of STDIN as a message. Implied if no message is given",
+		},
+	}
+	sendCmd = cli.Command{
+		Name:      "send",
+		Usage:     "Logs a message, or the lines of STDIN, through the configured loggers and outputs",
+		ArgsUsage: "[message] [key=value...]",
+		Flags:     sendFlags,
+		Action:    sendRun,
+	}
+)
+
+func sendRun(c *cli.Context) error {
+	args := []string(c.Args())
+	stdin := c.Bool("stdin") || len(args) == 0
+	var message string
+	if !stdin {
+		message, args = args[0], args[1:]
+	}
+	fields, err := parseFields(args)
+	if err != nil {
+		return err
+	}
+	logger, err := loadLogger(c)
+	if err != nil {
+		return err
+	}
+	// parsed after loading the configuration, which registers custom levels
+	lvl, err := level.Parse(c.String("level"))
+	if err != nil {
+		return fmt.Errorf("%v: %q", err, c.String("level"))
+	}
+	if fields != nil {
+		logger = logger.WithFields(fields)
+	}
+	if !stdin {
+		logger.Log(lvl, message)
+		return nil
+	}
+	return forEachLine(nil, func(line string) error {
+		if strings.TrimSpace(line) != "" {
+			logger.Log(lvl, line)
+		}
+		return nil
+	})
+}
+
+// loadLogger configures octolog with the configuration file given by the
+// config flag and returns the logger given by the logger flag.
+func loadLogger(c *cli.Context) (*octolog.Logger, error) {
+	if err := loadConfig(c.String("config"), c.IsSet("config")); err != nil {
+		return nil, err
+	}
+	name := c.String("logger")
+	if name == "" {
+		name = octolog.LoggerName
+	}
+	if logger := octolog.GetLogger(name); logger != nil {
+		return logger, nil
+	}
+	return octolog.NewLogger(name, nil), nil
+}
+
+// loadConfig configures octolog with the given configuration file. Unless
+// required, a missing file implies the defaults.
+func loadConfig(file string, required bool) error {
+	var paths []string
+	if _, err := os.Stat(file); err == nil {
+		paths = append(paths, file)
+	} else if required || !os.IsNotExist(err) {
+		return err
+	}
+	cfg, err := config.LoadE(paths...)
+	if err != nil {
+		return err
+	}
+	return octolog.ConfigureE(cfg)
+}
+
+// parseFields returns the fields given as key=value arguments.
+func parseFields(args []string) (map[string]interface{}, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]interface{}, len(args))
+	for i := range args {
+		j := strings.Index(args[i], "=")
+		if j < 1 {
+			return nil, errors.New("invalid field, expected key=value: " + args[i])
+		}
+		fields[args[i][:j]] = args[i][j+1:]
+	}
+	return fields, nil
+}
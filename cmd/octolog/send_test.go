@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+func TestSend(t *testing.T) {
+	defer octolog.Reset()
+	dir, err := ioutil.TempDir("", "octolog-send")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "send.log")
+	configFile := filepath.Join(dir, "logging.yml")
+	config := "defaultoutputs:\n  - file://" + logFile + "\n" +
+		"outputs:\n  - url: file://" + logFile + "\n" +
+		"    format: '{{.Level}} {{.Message}}{{range $k, $v := .Fields}} {{$k}}={{$v}}{{end}}'\n" +
+		"levels:\n  - name: AUDIT\n"
+	if err := ioutil.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		args     []string
+		stdin    string
+		expected []string
+	}{
+		{
+			args:     []string{"password changed", "user=bob"},
+			expected: []string{"INFO password changed user=bob"},
+		},
+		{
+			args:     []string{"--level", "AUDIT", "password changed"},
+			expected: []string{"AUDIT password changed"},
+		},
+		{
+			args:     []string{"--level", "warning", "--stdin", "step=migrate"},
+			stdin:    "first\n\nsecond",
+			expected: []string{"WARNING first step=migrate", "WARNING second step=migrate"},
+		},
+		{
+			args:     []string{"--stdin", "--level", "AUDIT"},
+			stdin:    "third\n",
+			expected: []string{"AUDIT third"},
+		},
+	} {
+		if err := ioutil.WriteFile(logFile, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		stdin := filepath.Join(dir, "stdin")
+		if err := ioutil.WriteFile(stdin, []byte(test.stdin), 0644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(stdin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		origStdin := os.Stdin
+		os.Stdin = f
+		err = runSend(t, append([]string{"--config", configFile}, test.args...))
+		os.Stdin = origStdin
+		f.Close()
+		if err != nil {
+			t.Fatalf("%q: %s", test.args, err)
+		}
+		b, err := ioutil.ReadFile(logFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Split(strings.TrimSpace(string(b)), "\n"); strings.Join(got, "|") != strings.Join(test.expected, "|") {
+			t.Errorf("%q: expected %q, got %q", test.args, test.expected, got)
+		}
+	}
+
+	if err := runSend(t, []string{"--config", configFile, "--level", "UNDEFINED", "message"}); err == nil {
+		t.Error("expected an error for an undefined log-level")
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := parseFields([]string{"user=bob", "query=a=b", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 3 || fields["user"] != "bob" || fields["query"] != "a=b" || fields["empty"] != "" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	for _, arg := range []string{"user", "=bob"} {
+		if _, err := parseFields([]string{arg}); err == nil {
+			t.Errorf("expected an error for %q", arg)
+		}
+	}
+}
+
+// runSend runs octolog send with the given arguments.
+func runSend(t *testing.T, args []string) error {
+	set := flag.NewFlagSet("send", flag.ContinueOnError)
+	for i := range sendFlags {
+		sendFlags[i].Apply(set)
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return sendRun(cli.NewContext(nil, set, nil))
+}
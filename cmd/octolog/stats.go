@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+	"github.com/urfave/cli"
+)
+
+var (
+	statsFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "from-format, f",
+			Usage: "Format of the input: json, logfmt, default, debug or a template. Defaults to detecting JSON, logfmt and the default format",
+		},
+		cli.IntFlag{
+			Name:  "top, n",
+			Value: 10,
+			Usage: "Number of loggers, call sites and messages to report (0 implies all)",
+		},
+		cli.DurationFlag{
+			Name:  "interval, i",
+			Value: time.Minute,
+			Usage: "Interval to count entries over time by",
+		},
+		cli.BoolFlag{
+			Name:  "json, j",
+			Usage: "Print the summary as JSON",
+		},
+	}
+	statsCmd = cli.Command{
+		Name:      "stats",
+		Usage:     "Summarizes the entries of log files or STDIN",
+		ArgsUsage: "[files...]",
+		Flags:     statsFlags,
+		Action:    statsRun,
+	}
+)
+
+// summary holds the statistics of log entries.
+type summary struct {
+	First     *time.Time `json:"first,omitempty"`
+	Last      *time.Time `json:"last,omitempty"`
+	Entries   int        `json:"entries"`
+	Undecoded int        `json:"undecoded"`
+	Levels    []count    `json:"levels"`
+	Loggers   []count    `json:"loggers"`
+	CallSites []count    `json:"callSites"`
+	Messages  []count    `json:"messages"`
+	OverTime  []count    `json:"overTime"`
+}
+
+// count is the number of entries with the same key.
+type count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// maxKeys limits the number of distinct messages and call sites counted, so
+// that stats runs in bounded memory. Further keys are counted as otherKey.
+const maxKeys = 10000
+
+// otherKey counts the entries with keys beyond maxKeys.
+const otherKey = "(other)"
+
+func statsRun(c *cli.Context) error {
+	dec, err := newDecoder(c.String("from-format"))
+	if err != nil {
+		return err
+	}
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", interval)
+	}
+	agg := newAggregator(interval)
+	if err := forEachEntry(c.Args(), dec, agg.add); err != nil {
+		return err
+	}
+	s := agg.summary(c.Int("top"))
+	if c.Bool("json") {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+	return s.writeTable(os.Stdout)
+}
+
+// aggregator counts entries by their level, logger, call site, message and
+// time.
+type aggregator struct {
+	s         summary
+	interval  time.Duration
+	levels    map[string]int
+	loggers   map[string]int
+	callSites map[string]int
+	messages  map[string]int
+	overTime  map[int64]int
+}
+
+func newAggregator(interval time.Duration) *aggregator {
+	return &aggregator{
+		interval:  interval,
+		levels:    map[string]int{},
+		loggers:   map[string]int{},
+		callSites: map[string]int{},
+		messages:  map[string]int{},
+		overTime:  map[int64]int{},
+	}
+}
+
+// add counts the given entry.
+func (a *aggregator) add(e *entry) error {
+	r := e.record
+	if r == nil {
+		a.s.Undecoded += len(e.lines)
+		return nil
+	}
+	a.s.Entries++
+	a.levels[r.Level]++
+	a.loggers[r.Logger]++
+	countKey(a.messages, r.Message)
+	if r.Func != "" || r.File != "" {
+		countKey(a.callSites, fmt.Sprintf("%s %s:%d", r.Func, r.File, r.Line))
+	}
+	if !r.Time.IsZero() {
+		t := r.Time
+		if a.s.First == nil || t.Before(*a.s.First) {
+			a.s.First = &t
+		}
+		if a.s.Last == nil || t.After(*a.s.Last) {
+			a.s.Last = &t
+		}
+		a.overTime[t.Truncate(a.interval).Unix()]++
+	}
+	return nil
+}
+
+// countKey counts the given key, or otherKey if the given counts hold
+// maxKeys keys already.
+func countKey(counts map[string]int, key string) {
+	if _, ok := counts[key]; !ok && len(counts) >= maxKeys {
+		key = otherKey
+	}
+	counts[key]++
+}
+
+// summary returns the summary of the counted entries, limited to the top n
+// loggers, call sites and messages (0 implies all).
+func (a *aggregator) summary(top int) summary {
+	s := a.s
+	s.Levels = sortedCounts(a.levels, 0, levelLess)
+	s.Loggers = sortedCounts(a.loggers, top, nil)
+	s.CallSites = sortedCounts(a.callSites, top, nil)
+	s.Messages = sortedCounts(a.messages, top, nil)
+	times := make([]int64, 0, len(a.overTime))
+	for t := range a.overTime {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	for i := range times {
+		s.OverTime = append(s.OverTime, count{
+			Key:   time.Unix(times[i], 0).Format("2006-01-02 15:04:05"),
+			Count: a.overTime[times[i]],
+		})
+	}
+	return s
+}
+
+// sortedCounts returns the given counts in the order given by less, or by
+// descending count, limited to the first n (0 implies all).
+func sortedCounts(counts map[string]int, n int, less func(a, b count) bool) []count {
+	out := make([]count, 0, len(counts))
+	for k, v := range counts {
+		out = append(out, count{Key: k, Count: v})
+	}
+	if less == nil {
+		less = func(a, b count) bool {
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Key < b.Key
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// levelLess orders registered log-levels by severity, followed by unknown
+// log-levels by name.
+func levelLess(a, b count) bool {
+	la, errA := level.Parse(a.Key)
+	lb, errB := level.Parse(b.Key)
+	switch {
+	case errA == nil && errB == nil:
+		return la < lb
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a.Key < b.Key
+	}
+}
+
+// writeTable writes this summary as tables.
+func (s summary) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "entries\t%d\n", s.Entries)
+	if s.Undecoded > 0 {
+		fmt.Fprintf(tw, "undecoded lines\t%d\n", s.Undecoded)
+	}
+	if s.First != nil {
+		fmt.Fprintf(tw, "first\t%s\n", s.First.Format(time.RFC3339Nano))
+		fmt.Fprintf(tw, "last\t%s\n", s.Last.Format(time.RFC3339Nano))
+	}
+	for _, section := range []struct {
+		title  string
+		counts []count
+	}{
+		{"LEVEL", s.Levels},
+		{"LOGGER", s.Loggers},
+		{"CALL SITE", s.CallSites},
+		{"MESSAGE", s.Messages},
+		{"TIME", s.OverTime},
+	} {
+		if len(section.counts) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s\tENTRIES\n", section.title)
+		for i := range section.counts {
+			fmt.Fprintf(tw, "%s\t%d\n", section.counts[i].Key, section.counts[i].Count)
+		}
+	}
+	return tw.Flush()
+}
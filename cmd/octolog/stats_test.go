@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAggregator(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		input    string
+		top      int
+		first    time.Time
+		last     time.Time
+		expected summary
+	}{
+		{
+			name: "levels by severity",
+			input: `{"time":"2019-10-10T10:10:10Z","level":"ERROR","logger":"main","message":"a"}
+{"time":"2019-10-10T10:11:10Z","level":"AUDIT","logger":"main","message":"a"}
+{"time":"2019-10-10T10:10:20Z","level":"DEBUG","logger":"db","message":"b"}
+{"time":"2019-10-10T10:12:10Z","level":"ERROR","logger":"db","message":"a"}`,
+			first: time.Date(2019, 10, 10, 10, 10, 10, 0, time.UTC),
+			last:  time.Date(2019, 10, 10, 10, 12, 10, 0, time.UTC),
+			expected: summary{
+				Entries:   4,
+				Levels:    []count{{"ERROR", 2}, {"DEBUG", 1}, {"AUDIT", 1}},
+				Loggers:   []count{{"db", 2}, {"main", 2}},
+				CallSites: []count{},
+				Messages:  []count{{"a", 3}, {"b", 1}},
+				OverTime:  []count{{minute(10), 2}, {minute(11), 1}, {minute(12), 1}},
+			},
+		},
+		{
+			name: "top call sites",
+			input: `level=INFO logger=main message=x func=main.a file=a.go line=1
+level=INFO logger=main message=x func=main.b file=b.go line=2
+level=INFO logger=main message=x func=main.b file=b.go line=2
+not an entry
+level=INFO logger=main message=y`,
+			top: 1,
+			expected: summary{
+				Entries:   4,
+				Undecoded: 1,
+				Levels:    []count{{"INFO", 4}},
+				Loggers:   []count{{"main", 4}},
+				CallSites: []count{{"main.b b.go:2", 2}},
+				Messages:  []count{{"x", 3}},
+			},
+		},
+	} {
+		dec, err := newDecoder("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		agg := newAggregator(time.Minute)
+		er := newEntryReader(strings.NewReader(test.input), dec)
+		for {
+			e, err := er.Next()
+			if err != nil {
+				break
+			}
+			agg.add(e)
+		}
+		s := agg.summary(test.top)
+		if s.First != nil && (!s.First.Equal(test.first) || !s.Last.Equal(test.last)) {
+			t.Errorf("%s: expected %s to %s, got %s to %s", test.name, test.first, test.last, s.First, s.Last)
+		}
+		s.First, s.Last = nil, nil
+		if !reflect.DeepEqual(s, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, s)
+		}
+	}
+}
+
+func TestCountKeyLimit(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < maxKeys+10; i++ {
+		countKey(counts, strings.Repeat("x", i))
+	}
+	countKey(counts, "")
+	if len(counts) != maxKeys+1 {
+		t.Errorf("expected %d keys, got %d", maxKeys+1, len(counts))
+	}
+	if counts[otherKey] != 10 || counts[""] != 2 {
+		t.Errorf("expected 10 other and 2 empty keys, got %d and %d", counts[otherKey], counts[""])
+	}
+}
+
+// minute returns the key of the given minute of 2019-10-10 10:00 UTC in
+// the entries over time.
+func minute(m int) string {
+	return time.Date(2019, 10, 10, 10, m, 0, 0, time.UTC).Local().Format("2006-01-02 15:04:05")
+}
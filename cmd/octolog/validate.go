@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/octogo/log/pkg/config"
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+var (
+	validateCmd = cli.Command{
+		Name:      "validate",
+		Usage:     "Checks configuration files for errors",
+		ArgsUsage: "[files...] (defaults to logging.yml)",
+		Action:    validateRun,
+	}
+)
+
+func validateRun(c *cli.Context) error {
+	files := c.Args()
+	if len(files) == 0 {
+		files = []string{"logging.yml"}
+	}
+	var failed int
+	for i := range files {
+		problems := validateFile(files[i])
+		for j := range problems {
+			fmt.Fprintln(os.Stderr, problems[j])
+		}
+		if len(problems) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return cli.NewExitError(fmt.Sprintf("%d of %d files invalid", failed, len(files)), 1)
+	}
+	return nil
+}
+
+// validateFile returns the problems of the given configuration file, each
+// prefixed with the file name and line. The file is validated as written and
+// interpolated, e.g. ${APP_LOG_URL:-file:///tmp/app.log}, but without the
+// overrides of the OCTOLOG_* environment variables.
+func validateFile(file string) []string {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	cfg, err := config.ReadRaw(file)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", file, err)}
+	}
+	cfg.Interpolate()
+	err = octolog.Validate(cfg)
+	if err == nil {
+		return nil
+	}
+	errs, ok := err.(config.Errors)
+	if !ok {
+		return []string{fmt.Sprintf("%s: %v", file, err)}
+	}
+	lines := make([]int, len(errs))
+	for i := range errs {
+		lines[i] = config.Line(data, errs[i].Path)
+	}
+	order := make([]int, len(errs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return lines[order[i]] < lines[order[j]] })
+	problems := make([]string, len(errs))
+	for i, k := range order {
+		if lines[k] > 0 {
+			problems[i] = fmt.Sprintf("%s:%d: %v", file, lines[k], errs[k])
+		} else {
+			problems[i] = fmt.Sprintf("%s: %v", file, errs[k])
+		}
+	}
+	return problems
+}
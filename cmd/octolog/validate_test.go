@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "octolog-validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Unsetenv("OCTOLOG_TEST_LOG_URL")
+
+	for _, test := range []struct {
+		config   string
+		problems []string
+	}{
+		{
+			config: "outputs:\n  - url: '${OCTOLOG_TEST_LOG_URL:-file://" + filepath.Join(dir, "app.log") + "}'\n",
+		},
+		{
+			config:   "outputs:\n  - url: file:///dev/stdout\n    format: '{{.Date}} {{.Mesage}}'\n",
+			problems: []string{"logging.yml:3: outputs[0].format: "},
+		},
+		{
+			config:   "loggers:\n  - name: main\n    wants: [WARN]\n",
+			problems: []string{"logging.yml:3: loggers[0].wants[0]: "},
+		},
+		{
+			config:   "unknown: true\n",
+			problems: []string{"logging.yml: "},
+		},
+	} {
+		file := filepath.Join(dir, "logging.yml")
+		if err := ioutil.WriteFile(file, []byte(test.config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		problems := validateFile(file)
+		if len(problems) != len(test.problems) {
+			t.Errorf("%q: expected %d problems, got %q", test.config, len(test.problems), problems)
+			continue
+		}
+		for i := range problems {
+			if expected := filepath.Join(dir, test.problems[i]); !strings.HasPrefix(problems[i], expected) {
+				t.Errorf("%q: expected %q, got %q", test.config, expected, problems[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/octogo/log/pkg/level"
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+var (
+	wrapFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "config, c",
+			Value: "logging.yml",
+			Usage: "Configuration file to load. Defaults are used if logging.yml does not exist",
+		},
+		cli.StringFlag{
+			Name:  "logger, n",
+			Usage: "Name of the logger to log with. Defaults to the configured root logger",
+		},
+		cli.StringFlag{
+			Name:  "stdout-level, o",
+			Value: "INFO",
+			Usage: "Log-level of lines written to STDOUT",
+		},
+		cli.StringFlag{
+			Name:  "stderr-level, e",
+			Value: "WARNING",
+			Usage: "Log-level of lines written to STDERR",
+		},
+	}
+	wrapCmd = cli.Command{
+		Name:      "wrap",
+		Usage:     "Runs a command and logs every line of its output, its exit status and runtime",
+		ArgsUsage: "-- command [args...]",
+		Flags:     wrapFlags,
+		Action:    wrapRun,
+	}
+)
+
+// forwardedSignals are the signals forwarded to wrapped commands.
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+func wrapRun(c *cli.Context) error {
+	args := c.Args()
+	if len(args) == 0 {
+		return errors.New("no command to wrap")
+	}
+	logger, err := loadLogger(c)
+	if err != nil {
+		return err
+	}
+	// parsed after loading the configuration, which registers custom levels
+	stdoutLevel, err := level.Parse(c.String("stdout-level"))
+	if err != nil {
+		return fmt.Errorf("%v: %q", err, c.String("stdout-level"))
+	}
+	stderrLevel, err := level.Parse(c.String("stderr-level"))
+	if err != nil {
+		return fmt.Errorf("%v: %q", err, c.String("stderr-level"))
+	}
+
+	child := exec.Command(args[0], args[1:]...)
+	child.Stdin = os.Stdin
+	cmd := octolog.Cmd(child, logger)
+	cmd.StdoutLevel, cmd.StderrLevel = stdoutLevel, stderrLevel
+	stop := cmd.Forward(forwardedSignals...)
+	defer stop()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		if code := child.ProcessState.ExitCode(); code > 0 {
+			return cli.NewExitError("", code)
+		}
+		return cli.NewExitError("", 1)
+	}
+	return nil
+}
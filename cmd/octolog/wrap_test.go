@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	octolog "github.com/octogo/log/pkg/log"
+	"github.com/urfave/cli"
+)
+
+func TestWrapExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	defer octolog.Reset()
+	dir, err := ioutil.TempDir("", "octolog-wrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "logging.yml")
+	config := "defaultoutputs:\n  - file://" + filepath.Join(dir, "wrap.log") + "\n"
+	if err := ioutil.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		script string
+		code   int
+	}{
+		{"exit 0", 0},
+		{"exit 3", 3},
+		{"kill -TERM $$", 1},
+	} {
+		set := flag.NewFlagSet("wrap", flag.ContinueOnError)
+		for i := range wrapFlags {
+			wrapFlags[i].Apply(set)
+		}
+		if err := set.Parse([]string{"--config", configFile, "--", "sh", "-c", test.script}); err != nil {
+			t.Fatal(err)
+		}
+		err := wrapRun(cli.NewContext(nil, set, nil))
+		code := 0
+		if exitErr, ok := err.(cli.ExitCoder); ok {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("%q: %s", test.script, err)
+		}
+		if code != test.code {
+			t.Errorf("%q: expected exit code %d, got %d", test.script, test.code, code)
+		}
+	}
+}
+
+func TestWrapCustomLevels(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	defer octolog.Reset()
+	dir, err := ioutil.TempDir("", "octolog-wrap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "wrap.log")
+	configFile := filepath.Join(dir, "logging.yml")
+	config := "defaultoutputs:\n  - file://" + logFile + "\n" +
+		"outputs:\n  - url: file://" + logFile + "\n    format: '{{.Level}} {{.Message}}'\n" +
+		"levels:\n  - name: AUDIT\n  - name: ALERT\n"
+	if err := ioutil.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	set := flag.NewFlagSet("wrap", flag.ContinueOnError)
+	for i := range wrapFlags {
+		wrapFlags[i].Apply(set)
+	}
+	args := []string{"--config", configFile, "--stdout-level", "AUDIT", "--stderr-level", "ALERT", "--", "sh", "-c", "echo out; echo err >&2"}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if err := wrapRun(cli.NewContext(nil, set, nil)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"AUDIT out\n", "ALERT err\n"} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("expected %q in %q", expected, b)
+		}
+	}
+}
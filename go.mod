@@ -6,4 +6,5 @@ require (
 	github.com/jinzhu/configor v1.1.1
 	github.com/urfave/cli v1.22.1
 	golang.org/x/sys v0.0.0-20191010194322-b09406accb47
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -19,15 +19,37 @@ func LoadConfig(paths ...string) *config.Config {
 }
 
 // Init initialized octolog.
+// Without a configuration file, the defaults of the standard logger and its
+// outputs are only overridden by environment variables (see
+// config.EnvPrefix).
 func Init() {
 	if _, err := os.Stat(ConfigFile); err != nil {
-		log.Init()
+		log.Configure(envConfig())
 	} else {
 		log.Configure(config.Load(ConfigFile))
 	}
 }
 
+// envConfig returns the defaults of package log, overridden by environment
+// variables. The standard logger is listed, so that it can be overridden.
+func envConfig() *config.Config {
+	c := &config.Config{
+		DefaultFormat:  log.DefaultLogFormat,
+		LoggerName:     log.LoggerName,
+		DefaultOutputs: log.DefaultOutputs,
+		Loggers:        []config.Logger{{Name: log.LoggerName}},
+	}
+	c.ApplyEnv()
+	return c
+}
+
 // InitWithConfig intializes octolog with a custom configuration.
 func InitWithConfig(c *config.Config) {
 	log.Configure(c)
 }
+
+// InitWithConfigE validates the given configuration and initializes octolog
+// with it. On errors, nothing is initialized.
+func InitWithConfigE(c *config.Config) error {
+	return log.ConfigureE(c)
+}
@@ -52,3 +52,20 @@ func ParseLevels(levels ...string) []level.Level {
 	}
 	return out
 }
+
+// ParseThreshold returns all registered levels that are at least as severe as
+// the level with the given name.
+func ParseThreshold(name string) []level.Level {
+	threshold, err := level.Parse(name)
+	if err != nil {
+		panic(err)
+	}
+	all := level.Levels()
+	out := make([]level.Level, 0, len(all))
+	for i := range all {
+		if all[i] <= threshold {
+			out = append(out, all[i])
+		}
+	}
+	return out
+}
@@ -1,6 +1,8 @@
 package log
 
 import (
+	"os/exec"
+
 	"github.com/octogo/log/pkg/level"
 	"github.com/octogo/log/pkg/log"
 )
@@ -15,30 +17,56 @@ func New(name string, wants []level.Level, outputs ...string) *log.Logger {
 
 // Println logs the given value with log-level INFO.
 func Println(v interface{}) {
+	log.Helper()
 	log.Println(v)
 }
 
 // Printf wraps Println and supports string formatting.
 func Printf(f string, args ...interface{}) {
+	log.Helper()
 	log.Printf(f, args...)
 }
 
 // Log is an alias for Println.
 func Log(v interface{}) {
+	log.Helper()
 	log.Println(v)
 }
 
 // Logf is an alias for Printf.
 func Logf(f string, args ...interface{}) {
+	log.Helper()
 	log.Printf(f, args...)
 }
 
 // Fatal logs the given value with log-level ERROR and exits with RC-1.
 func Fatal(v interface{}) {
+	log.Helper()
 	log.Fatal(v)
 }
 
 // Fatalf wraps Fatal() and supports string formatting.
 func Fatalf(f string, args ...interface{}) {
+	log.Helper()
 	log.Fatalf(f, args...)
 }
+
+// HandleSignals makes SIGUSR1 add DEBUG to the log-levels of all loggers and
+// outputs and SIGUSR2 restore their configured log-levels.
+// The returned function stops handling the signals.
+func HandleSignals() (stop func()) {
+	return log.HandleSignals()
+}
+
+// Watch polls the configuration file at the given path and applies changes
+// to it to the live loggers and outputs.
+// The returned function stops watching the file.
+func Watch(path string) (stop func()) {
+	return log.Watch(path)
+}
+
+// Cmd returns a Command that logs every line the given command writes to
+// STDOUT and STDERR through the given Logger, as well as its exit status.
+func Cmd(cmd *exec.Cmd, logger *log.Logger) *log.Command {
+	return log.Cmd(cmd, logger)
+}
@@ -0,0 +1,367 @@
+// Package admin provides an HTTP handler for inspecting and changing the
+// log-levels and formats of a running program.
+//
+// Mount it under a path of your choice, preferably behind authentication:
+//
+//	http.Handle("/debug/octolog/", http.StripPrefix("/debug/octolog", admin.Handler()))
+//
+// The handler serves the following endpoints:
+//
+//	GET  /         levels, loggers and outputs
+//	GET  /levels   registered log-levels
+//	GET  /loggers  registered loggers
+//	POST /loggers  {"name": "main", "wants": ["DEBUG"], "ttl": "10m"}
+//	GET  /outputs  registered outputs
+//	POST /outputs  {"url": "file:///dev/stdout", "wants": [], "format": "json", "ttl": "10m"}
+//
+// An empty list of wants implies 'all', omitted fields remain unchanged.
+// With a TTL, the change is reverted automatically after the given duration.
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+	"github.com/octogo/log/pkg/log"
+)
+
+// Level describes a registered log-level.
+type Level struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// Logger describes a registered Logger.
+type Logger struct {
+	Name    string   `json:"name"`
+	Wants   []string `json:"wants"`
+	Outputs []string `json:"outputs"`
+}
+
+// Output describes a registered Output.
+type Output struct {
+	URL    string   `json:"url"`
+	Wants  []string `json:"wants"`
+	Format string   `json:"format,omitempty"`
+}
+
+// State describes all registered log-levels, loggers and outputs.
+type State struct {
+	Levels  []Level  `json:"levels"`
+	Loggers []Logger `json:"loggers"`
+	Outputs []Output `json:"outputs"`
+}
+
+// LoggerChange describes a change to a Logger.
+type LoggerChange struct {
+	Name  string    `json:"name"`
+	Wants *[]string `json:"wants"`
+	TTL   string    `json:"ttl"`
+}
+
+// OutputChange describes a change to an Output.
+type OutputChange struct {
+	URL    string    `json:"url"`
+	Wants  *[]string `json:"wants"`
+	Format *string   `json:"format"`
+	TTL    string    `json:"ttl"`
+}
+
+// wantedLevels is implemented by outputs that expose their wanted levels.
+type wantedLevels interface {
+	WantedLevels() []level.Level
+}
+
+// formatter is implemented by outputs that expose their log-format.
+type formatter interface {
+	Format() string
+}
+
+// Handler returns an http.Handler serving the admin endpoints.
+func Handler() http.Handler {
+	h := &handler{
+		mux:     http.NewServeMux(),
+		reverts: map[string]*revert{},
+		mu:      &sync.Mutex{},
+	}
+	h.mux.HandleFunc("/", h.serveState)
+	h.mux.HandleFunc("/levels", h.serveLevels)
+	h.mux.HandleFunc("/loggers", h.serveLoggers)
+	h.mux.HandleFunc("/outputs", h.serveOutputs)
+	return h
+}
+
+type handler struct {
+	mux     *http.ServeMux
+	reverts map[string]*revert
+	mu      *sync.Mutex
+}
+
+// revert holds the function restoring the state before the first of a
+// series of temporary changes.
+type revert struct {
+	restore func()
+	timer   *time.Timer
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) serveState(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, State{
+		Levels:  levels(),
+		Loggers: loggers(),
+		Outputs: outputs(),
+	})
+}
+
+func (h *handler) serveLevels(w http.ResponseWriter, r *http.Request) {
+	if allowMethods(w, r, http.MethodGet) {
+		writeJSON(w, levels())
+	}
+}
+
+func (h *handler) serveLoggers(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodGet {
+		writeJSON(w, loggers())
+		return
+	}
+	var change LoggerChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logger := log.GetLogger(change.Name)
+	if logger == nil {
+		http.Error(w, "undefined logger: "+change.Name, http.StatusNotFound)
+		return
+	}
+	ttl, err := parseTTL(change.TTL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if change.Wants != nil {
+		wants, err := parseLevels(*change.Wants)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		previous := logger.WantedLevels()
+		h.apply("logger:"+logger.Name, ttl, func() {
+			logger.SetWants(wants)
+		}, func() {
+			logger.SetWants(previous)
+		})
+	}
+	writeJSON(w, describeLogger(logger))
+}
+
+func (h *handler) serveOutputs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodGet {
+		writeJSON(w, outputs())
+		return
+	}
+	var change OutputChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	output := log.GetOutput(change.URL)
+	if output == nil {
+		http.Error(w, "undefined output: "+change.URL, http.StatusNotFound)
+		return
+	}
+	ttl, err := parseTTL(change.TTL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var wants []level.Level
+	if change.Wants != nil {
+		if wants, err = parseLevels(*change.Wants); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := output.(wantedLevels); !ok {
+			http.Error(w, "output does not expose its wants: "+change.URL, http.StatusNotImplemented)
+			return
+		}
+	}
+	if change.Format != nil {
+		if err := log.ValidateFormat(*change.Format); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := output.(formatter); !ok {
+			http.Error(w, "output does not expose its format: "+change.URL, http.StatusNotImplemented)
+			return
+		}
+	}
+	if change.Wants != nil {
+		previous := output.(wantedLevels).WantedLevels()
+		h.apply("output-wants:"+change.URL, ttl, func() {
+			output.SetWants(wants)
+		}, func() {
+			output.SetWants(previous)
+		})
+	}
+	if change.Format != nil {
+		previous := output.(formatter).Format()
+		h.apply("output-format:"+change.URL, ttl, func() {
+			output.SetFormat(*change.Format)
+		}, func() {
+			output.SetFormat(previous)
+		})
+	}
+	writeJSON(w, describeOutput(output))
+}
+
+// apply applies a change under the given key. With a TTL, the change is
+// reverted after it passes. Pending reverts of the key are cancelled, but
+// the state before the first temporary change is what gets restored.
+func (h *handler) apply(key string, ttl time.Duration, change, restore func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	pending, exists := h.reverts[key]
+	if exists {
+		pending.timer.Stop()
+		delete(h.reverts, key)
+		restore = pending.restore
+	}
+	change()
+	if ttl <= 0 {
+		return
+	}
+	rev := &revert{restore: restore}
+	rev.timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.reverts[key] == rev {
+			delete(h.reverts, key)
+			rev.restore()
+		}
+	})
+	h.reverts[key] = rev
+}
+
+func levels() []Level {
+	all := level.Levels()
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	out := make([]Level, len(all))
+	for i := range all {
+		out[i] = Level{Name: all[i].String(), Value: int(all[i])}
+	}
+	return out
+}
+
+func loggers() []Logger {
+	all := log.Loggers()
+	out := make([]Logger, len(all))
+	for i := range all {
+		out[i] = describeLogger(all[i])
+	}
+	return out
+}
+
+func describeLogger(logger *log.Logger) Logger {
+	return Logger{
+		Name:    logger.Name,
+		Wants:   levelNames(logger.WantedLevels()),
+		Outputs: logger.OutputURLs(),
+	}
+}
+
+func outputs() []Output {
+	all := log.Outputs()
+	out := make([]Output, len(all))
+	for i := range all {
+		out[i] = describeOutput(all[i])
+	}
+	return out
+}
+
+func describeOutput(output log.Output) Output {
+	described := Output{URL: output.URL()}
+	if o, ok := output.(wantedLevels); ok {
+		described.Wants = levelNames(o.WantedLevels())
+	}
+	if o, ok := output.(formatter); ok {
+		described.Format = o.Format()
+	}
+	return described
+}
+
+// levelNames returns the names of the given levels, where nil implies 'all'.
+func levelNames(levels []level.Level) []string {
+	if levels == nil {
+		levels = level.Levels()
+		sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	}
+	names := make([]string, len(levels))
+	for i := range levels {
+		names[i] = levels[i].String()
+	}
+	return names
+}
+
+// parseLevels parses the given level-names, where none implies 'all'.
+func parseLevels(names []string) ([]level.Level, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	levels := make([]level.Level, len(names))
+	for i := range names {
+		lvl, err := level.Parse(names[i])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %q", err, names[i])
+		}
+		levels[i] = lvl
+	}
+	return levels, nil
+}
+
+func parseTTL(ttl string) (time.Duration, error) {
+	if ttl == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(ttl)
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for i := range methods {
+		if r.Method == methods[i] {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
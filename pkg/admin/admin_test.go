@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+	"github.com/octogo/log/pkg/log"
+)
+
+func TestLoggerChangeWithTTL(t *testing.T) {
+	logger := log.NewLogger("TEST-ADMIN", []level.Level{level.ERROR})
+	h := Handler()
+
+	req := httptest.NewRequest(http.MethodPost, "/loggers", strings.NewReader(
+		`{"name": "TEST-ADMIN", "wants": ["ERROR", "DEBUG"], "ttl": "50ms"}`,
+	))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %v, got %v: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	var described Logger
+	if err := json.Unmarshal(rec.Body.Bytes(), &described); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(described.Wants, ",") != "ERROR,DEBUG" {
+		t.Errorf("expected %v, got %v", "ERROR,DEBUG", described.Wants)
+	}
+	if !logger.Wants(level.DEBUG) {
+		t.Errorf("expected %v, got %v", true, false)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if logger.Wants(level.DEBUG) {
+		t.Errorf("expected %v, got %v", false, true)
+	}
+}
+
+func TestInvalidChange(t *testing.T) {
+	log.NewLogger("TEST-ADMIN-INVALID", nil)
+	for body, code := range map[string]int{
+		`{"name": "TEST-ADMIN-INVALID", "wants": ["WARN"]}`: http.StatusBadRequest,
+		`{"name": "TEST-ADMIN-UNDEFINED"}`:                  http.StatusNotFound,
+		`{"name": "TEST-ADMIN-INVALID", "ttl": "soon"}`:     http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/loggers", strings.NewReader(body)))
+		if rec.Code != code {
+			t.Errorf("expected %v, got %v for %s", code, rec.Code, body)
+		}
+	}
+}
+
+func TestInvalidOutputFormat(t *testing.T) {
+	f, err := ioutil.TempFile("", "octolog-admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	url := "file://" + f.Name()
+	log.NewFileOutput(f, nil, "{{.Message}}")
+	defer log.UnregisterOutput(url)
+
+	body := `{"url": "` + url + `", "format": "{{.Msg}}"}`
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/outputs", strings.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected %v, got %v: %s", http.StatusBadRequest, rec.Code, rec.Body)
+	}
+	if format := log.GetOutput(url).(interface{ Format() string }).Format(); format != "{{.Message}}" {
+		t.Errorf("expected %q, got %q", "{{.Message}}", format)
+	}
+}
@@ -3,6 +3,7 @@ package color
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -40,7 +41,7 @@ func (seq sequence) String() string {
 	}
 	colors := make([]string, len(seq.Colors))
 	for i := range seq.Colors {
-		colors[i] = seq.Colors[i].String()
+		colors[i] = seq.Colors[i].Degrade(ColorProfile).code()
 	}
 	out += strings.Join(colors, ";")
 	out += "m"
@@ -64,14 +65,7 @@ func (seq *sequence) SetAttribute(attr Attribute) error {
 
 func (seq *sequence) SetColors(colors []Color) error {
 	for i := range colors {
-		colorValid := false
-		for j := range Colors {
-			if Colors[j] == colors[i] {
-				colorValid = true
-				break
-			}
-		}
-		if !colorValid {
+		if !colors[i].valid() {
 			return fmt.Errorf("invalid ANSII color code: %d", colors[i])
 		}
 	}
@@ -122,11 +116,12 @@ var Attributes = []Attribute{
 	Invisible,
 }
 
-// Color is defined as a type of int.
+// Color is defined as a type of int. Besides the basic and bright ANSII
+// codes, it holds 256-colors (see Color256) and 24-bit colors (see RGB).
 type Color int
 
 func (c Color) String() string {
-	return fmt.Sprintf("%d", c)
+	return c.code()
 }
 
 // Colors
@@ -151,7 +146,8 @@ const (
 	BGWhite   Color = 47
 )
 
-// Colors contains the list of all supported FGColors and BGColors
+// Colors contains the list of all supported basic and bright FGColors and
+// BGColors
 var (
 	FGColors = []Color{
 		Black,
@@ -173,9 +169,37 @@ var (
 		BGCyan,
 		BGWhite,
 	}
-	Colors = append(FGColors, BGColors...)
+	BrightFGColors = []Color{
+		BrightBlack,
+		BrightRed,
+		BrightGreen,
+		BrightYellow,
+		BrightBlue,
+		BrightMagenta,
+		BrightCyan,
+		BrightWhite,
+	}
+	BrightBGColors = []Color{
+		BGBrightBlack,
+		BGBrightRed,
+		BGBrightGreen,
+		BGBrightYellow,
+		BGBrightBlue,
+		BGBrightMagenta,
+		BGBrightCyan,
+		BGBrightWhite,
+	}
+	Colors = concat(FGColors, BGColors, BrightFGColors, BrightBGColors)
 )
 
+func concat(lists ...[]Color) []Color {
+	var colors []Color
+	for i := range lists {
+		colors = append(colors, lists[i]...)
+	}
+	return colors
+}
+
 // New returns an ANSII escape sequence based on the given attributes and
 // colors.
 func New(attr Attribute, colors ...Color) Sequence {
@@ -192,3 +216,73 @@ func NewLiteral(literal string) Sequence {
 		Literal: literal,
 	}
 }
+
+// Names maps the supported color names to their foreground colors.
+var Names = map[string]Color{
+	"black":   Black,
+	"red":     Red,
+	"green":   Green,
+	"yellow":  Yellow,
+	"blue":    Blue,
+	"magenta": Magenta,
+	"cyan":    Cyan,
+	"white":   White,
+
+	"bright-black":   BrightBlack,
+	"bright-red":     BrightRed,
+	"bright-green":   BrightGreen,
+	"bright-yellow":  BrightYellow,
+	"bright-blue":    BrightBlue,
+	"bright-magenta": BrightMagenta,
+	"bright-cyan":    BrightCyan,
+	"bright-white":   BrightWhite,
+}
+
+// Parse returns the ANSII escape sequence for the given color name (e.g. red
+// or bright-red), 24-bit color (e.g. #ff8800 or #f80), color of the
+// 256-color palette (e.g. color(208)) or the given literal list of ANSII
+// codes (e.g. 1;31).
+func Parse(s string) (Sequence, error) {
+	if c, ok := Names[strings.ToLower(s)]; ok {
+		return New(NormalDisplay, c), nil
+	}
+	if strings.HasPrefix(s, "#") {
+		c, err := parseHex(s[1:])
+		if err != nil {
+			return nil, fmt.Errorf("undefined color: %q", s)
+		}
+		return New(NormalDisplay, c), nil
+	}
+	if strings.HasPrefix(s, "color(") && strings.HasSuffix(s, ")") {
+		n, err := strconv.ParseUint(s[len("color("):len(s)-1], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("undefined color: %q", s)
+		}
+		return New(NormalDisplay, Color256(uint8(n))), nil
+	}
+	if s == "" {
+		return nil, fmt.Errorf("undefined color: %q", s)
+	}
+	for _, code := range strings.Split(s, ";") {
+		if code == "" || strings.Trim(code, "0123456789") != "" {
+			return nil, fmt.Errorf("undefined color: %q", s)
+		}
+	}
+	return NewLiteral(s), nil
+}
+
+// parseHex returns the 24-bit color of the given hex triplet, e.g. ff8800, or
+// its shorthand, e.g. f80.
+func parseHex(hex string) (Color, error) {
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("invalid hex color: %q", hex)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
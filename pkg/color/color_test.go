@@ -17,3 +17,69 @@ func TestNew(t *testing.T) {
 		}
 	}
 }
+
+func TestParse(t *testing.T) {
+	defer func(p Profile) { ColorProfile = p }(ColorProfile)
+	ColorProfile = TrueColor
+	for s, expected := range map[string]string{
+		"red":        New(NormalDisplay, Red).String(),
+		"CYAN":       New(NormalDisplay, Cyan).String(),
+		"1;31":       NewLiteral("1;31").String(),
+		"bright-red": "\x1b[0;91m",
+		"#ff8800":    "\x1b[0;38;2;255;136;0m",
+		"#F80":       "\x1b[0;38;2;255;136;0m",
+		"color(208)": "\x1b[0;38;5;208m",
+	} {
+		seq, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seq.String() != expected {
+			t.Errorf("expected %q, got %q", expected, seq.String())
+		}
+	}
+	for _, s := range []string{"", "purple", "1;;31", "1;red", "#ff88", "#gg8800", "color(256)", "color()"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestExtendedColors(t *testing.T) {
+	seq := New(Bold, RGB(255, 136, 0), BGColor256(17))
+	if err := seq.SetColors([]Color{RGB(255, 136, 0), BGColor256(17)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := seq.SetColors([]Color{Color(38)}); err == nil {
+		t.Error("expected an error for color code 38")
+	}
+
+	defer func(p Profile) { ColorProfile = p }(ColorProfile)
+	seq = New(Bold, RGB(255, 136, 0), BGColor256(17))
+	for p, expected := range map[Profile]string{
+		TrueColor: "\x1b[1;38;2;255;136;0;48;5;17m",
+		ANSI256:   "\x1b[1;38;5;208;48;5;17m",
+		ANSI:      "\x1b[1;33;40m",
+	} {
+		ColorProfile = p
+		if seq.String() != expected {
+			t.Errorf("profile %d: expected %q, got %q", p, expected, seq.String())
+		}
+	}
+
+	for c, expected := range map[Color]Color{
+		Color256(9):       BrightRed,
+		BGColor256(2):     BGGreen,
+		Color256(231):     BrightWhite,
+		RGB(0, 0, 0):      Black,
+		BGRGB(200, 0, 10): BGRed,
+		Magenta:           Magenta,
+	} {
+		if degraded := c.Degrade(ANSI); degraded != expected {
+			t.Errorf("expected %v to degrade to %v, got %v", c, expected, degraded)
+		}
+	}
+	if c := RGB(128, 128, 128).Degrade(ANSI256); c != Color256(244) {
+		t.Errorf("expected %v, got %v", Color256(244), c)
+	}
+}
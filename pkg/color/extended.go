@@ -0,0 +1,228 @@
+package color
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Bright colors
+const (
+	// foreground colors
+	BrightBlack   Color = 90
+	BrightRed     Color = 91
+	BrightGreen   Color = 92
+	BrightYellow  Color = 93
+	BrightBlue    Color = 94
+	BrightMagenta Color = 95
+	BrightCyan    Color = 96
+	BrightWhite   Color = 97
+	// background colors
+	BGBrightBlack   Color = 100
+	BGBrightRed     Color = 101
+	BGBrightGreen   Color = 102
+	BGBrightYellow  Color = 103
+	BGBrightBlue    Color = 104
+	BGBrightMagenta Color = 105
+	BGBrightCyan    Color = 106
+	BGBrightWhite   Color = 107
+)
+
+// Extended colors are encoded in the bits above the basic ANSII codes: the
+// kind of color, whether it is a background color and the color itself.
+const (
+	kind256    Color = 1 << 24
+	kindRGB    Color = 2 << 24
+	kindMask   Color = 3 << 24
+	background Color = 1 << 26
+)
+
+// Color256 returns the foreground color n (0-255) of the 256-color palette.
+func Color256(n uint8) Color {
+	return kind256 | Color(n)
+}
+
+// BGColor256 returns the background color n (0-255) of the 256-color palette.
+func BGColor256(n uint8) Color {
+	return background | Color256(n)
+}
+
+// RGB returns the given 24-bit foreground color.
+func RGB(r, g, b uint8) Color {
+	return kindRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// BGRGB returns the given 24-bit background color.
+func BGRGB(r, g, b uint8) Color {
+	return background | RGB(r, g, b)
+}
+
+// code returns the ANSII code of this color, e.g. 31, 38;5;208 or
+// 48;2;255;136;0.
+func (c Color) code() string {
+	layer := 38
+	if c&background != 0 {
+		layer = 48
+	}
+	switch c & kindMask {
+	case kind256:
+		return fmt.Sprintf("%d;5;%d", layer, c&0xff)
+	case kindRGB:
+		r, g, b := c.rgb()
+		return fmt.Sprintf("%d;2;%d;%d;%d", layer, r, g, b)
+	}
+	return fmt.Sprintf("%d", int(c))
+}
+
+// valid returns true if this color is a supported ANSII color.
+func (c Color) valid() bool {
+	switch c & kindMask {
+	case kind256:
+		return c&^(background|kind256) <= 0xff
+	case kindRGB:
+		return c&^(background|kindRGB) <= 0xffffff
+	}
+	for i := range Colors {
+		if Colors[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// rgb returns the red, green and blue components of this color, which must be
+// a 256-color or 24-bit color.
+func (c Color) rgb() (r, g, b uint8) {
+	if c&kindMask == kindRGB {
+		return uint8(c >> 16), uint8(c >> 8), uint8(c)
+	}
+	n := int(c & 0xff)
+	switch {
+	case n < 16:
+		p := palette16[n]
+		return p[0], p[1], p[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	default:
+		gray := uint8(8 + 10*(n-232))
+		return gray, gray, gray
+	}
+}
+
+// Degrade returns the closest color to this color supported by terminals of
+// the given profile.
+func (c Color) Degrade(p Profile) Color {
+	kind := c & kindMask
+	if kind == 0 || p >= TrueColor || p == ANSI256 && kind == kind256 {
+		return c
+	}
+	bg := c & background
+	if p == ANSI256 {
+		return bg | Color256(nearest256(c.rgb()))
+	}
+	if kind == kind256 && c&0xff < 16 {
+		return basic16(int(c&0xff), bg != 0)
+	}
+	r, g, b := c.rgb()
+	best := 0
+	for i := 1; i < len(palette16); i++ {
+		if distance(r, g, b, palette16[i]) < distance(r, g, b, palette16[best]) {
+			best = i
+		}
+	}
+	return basic16(best, bg != 0)
+}
+
+// basic16 returns the basic or bright color with the given index (0-15) of
+// the 16-color palette.
+func basic16(i int, bg bool) Color {
+	c := Black + Color(i)
+	if i >= 8 {
+		c = BrightBlack + Color(i-8)
+	}
+	if bg {
+		c += BGBlack - Black
+	}
+	return c
+}
+
+// nearest256 returns the index of the color of the 256-color palette closest
+// to the given color, out of its color cube and grayscale ramp.
+func nearest256(r, g, b uint8) uint8 {
+	cube := func(v uint8) int {
+		best := 0
+		for i := range cubeLevels {
+			if absDiff(v, cubeLevels[i]) < absDiff(v, cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ri, gi, bi := cube(r), cube(g), cube(b)
+	cubeColor := [3]uint8{cubeLevels[ri], cubeLevels[gi], cubeLevels[bi]}
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayIndex := (avg - 3) / 10
+	if grayIndex < 0 {
+		grayIndex = 0
+	} else if grayIndex > 23 {
+		grayIndex = 23
+	}
+	gray := uint8(8 + 10*grayIndex)
+	if distance(r, g, b, [3]uint8{gray, gray, gray}) < distance(r, g, b, cubeColor) {
+		return uint8(232 + grayIndex)
+	}
+	return uint8(16 + 36*ri + 6*gi + bi)
+}
+
+func distance(r, g, b uint8, c [3]uint8) int {
+	dr, dg, db := absDiff(r, c[0]), absDiff(g, c[1]), absDiff(b, c[2])
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+// cubeLevels are the intensities of the 6x6x6 color cube of the 256-color
+// palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// palette16 holds the (xterm) values of the 16 basic and bright colors.
+var palette16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// Profile is defined as the colors supported by a terminal.
+type Profile int
+
+// Profiles
+const (
+	ANSI      Profile = iota // the 8 basic and 8 bright colors
+	ANSI256                  // the 256-color palette
+	TrueColor                // 24-bit colors
+)
+
+// ColorProfile is the profile that colors of sequences are degraded to.
+// It defaults to the profile detected by DetectProfile.
+var ColorProfile = DetectProfile()
+
+// DetectProfile returns the profile of the terminal, as announced by the
+// environment variables COLORTERM (truecolor or 24bit) and TERM (*256color*).
+func DetectProfile() Profile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return ANSI256
+	}
+	return ANSI
+}
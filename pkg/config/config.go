@@ -2,39 +2,108 @@ package config
 
 import "github.com/jinzhu/configor"
 
+// DefaultLevelColor is the color of configured log-levels without color.
+// It is not a default tag, as configor would never stop looking for further
+// levels in the environment.
+const DefaultLevelColor = "magenta"
+
 // Config is a data container for loaded configuration.
+// The description and schema tags are used by Schema.
 type Config struct {
-	DefaultFormat  string `default:"{{.Date}} {{.Time}} {{.Logger}} {{.Level}} {{.Message}}"`
-	LoggerName     string `default:"octolog"`
-	DefaultOutputs []string
-	Levels         []Level
-	Outputs        []Output
-	Loggers        []Logger
+	DefaultFormat  string   `default:"'{{.Date}} {{.Time}} {{.Logger}} {{.Level}} {{.Message}}'" description:"log-format of outputs without explicit format: a template, json or logfmt"`
+	LoggerName     string   `default:"octolog" description:"name of the standard logger"`
+	DefaultOutputs []string `description:"URLs of the outputs of loggers without explicit outputs"`
+	Levels         []Level  `description:"log-levels to register and their colors"`
+	Outputs        []Output `description:"outputs to initialize upon startup"`
+	Loggers        []Logger `description:"loggers to initialize upon startup"`
 }
 
 // Level is a helper for loading level configuration.
 type Level struct {
-	Name  string
-	Color string `default:"magenta"`
+	Name  string `schema:"required" description:"name of the log-level"`
+	Color string `description:"color name, #rrggbb, color(0-255) or literal ANSII codes, e.g. bright-red, #ff8800, color(208) or 1;31 (default: magenta)"`
 }
 
 // Output is a helper for loading output configuration.
 type Output struct {
-	URL    string `required:"true"`
-	Wants  []string
-	Format string
+	URL        string   `schema:"required" description:"URL of the output, e.g. file:///var/log/app.log"`
+	Wants      []string `description:"log-levels to log, none implies all"`
+	Format     string   `description:"log-format: a template, json or logfmt"`
+	Stacktrace string   `description:"log-level from which on stack traces are logged"`
+	Dedup      string   `description:"duration after which repeated messages are summarized, e.g. 10s"`
 }
 
 // Logger is a helper for loading logger configuration.
 type Logger struct {
-	Name    string `required:"true"`
-	Wants   []string
-	Outputs []string
+	Name     string   `schema:"required" description:"unique name of the logger"`
+	Wants    []string `description:"log-levels to log, none implies all"`
+	Outputs  []string `description:"URLs of the outputs, none implies defaultoutputs"`
+	Sampling Sampling `description:"limits the number of entries per interval"`
+	Dedup    string   `description:"duration after which repeated messages are summarized, e.g. 10s"`
+}
+
+// Sampling is a helper for loading sampling configuration of a logger.
+type Sampling struct {
+	Interval   string `description:"sampling interval, e.g. 1s (default)"`
+	First      int    `schema:"minimum=0" description:"number of entries logged per interval"`
+	Thereafter int    `schema:"minimum=0" description:"log every n-th entry after the first ones"`
+	By         string `schema:"enum=message|caller" description:"group entries by message (default) or caller"`
 }
 
-// Load returns the loaded configuration.
+// Load returns the loaded configuration, interpolated and overridden by
+// environment variables.
 func Load(paths ...string) *Config {
 	config := &Config{}
 	configor.Load(config, paths...)
+	config.setDefaults()
+	config.Interpolate()
+	config.ApplyEnv()
 	return config
 }
+
+// LoadE returns the loaded configuration, or the error that occurred while
+// loading it or the Errors found validating it.
+func LoadE(paths ...string) (*Config, error) {
+	config, err := Read(paths...)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Read returns the loaded configuration without validating it, or the error
+// that occurred while loading it. Unlike Load, it rejects unknown keys.
+func Read(paths ...string) (*Config, error) {
+	config, err := ReadRaw(paths...)
+	if err != nil {
+		return nil, err
+	}
+	config.Interpolate()
+	config.ApplyEnv()
+	return config, nil
+}
+
+// ReadRaw returns the loaded configuration as written, neither interpolated
+// nor overridden by environment variables, or the error that occurred while
+// loading it.
+func ReadRaw(paths ...string) (*Config, error) {
+	config := &Config{}
+	loader := configor.New(&configor.Config{ENVPrefix: "-", ErrorOnUnmatchedKeys: true})
+	if err := loader.Load(config, paths...); err != nil {
+		return nil, err
+	}
+	config.setDefaults()
+	return config, nil
+}
+
+// setDefaults applies the defaults that are not given by default tags.
+func (c *Config) setDefaults() {
+	for i := range c.Levels {
+		if c.Levels[i].Color == "" {
+			c.Levels[i].Color = DefaultLevelColor
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLoadEDefaults(t *testing.T) {
+	c, err := LoadE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DefaultFormat != "{{.Date}} {{.Time}} {{.Logger}} {{.Level}} {{.Message}}" || c.LoggerName != "octolog" {
+		t.Errorf("unexpected defaults: %q, %q", c.DefaultFormat, c.LoggerName)
+	}
+
+	f, err := ioutil.TempFile("", "octolog-*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("levels:\n  - name: AUDIT\n")
+	f.Close()
+	if c, err = LoadE(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Levels) != 1 || c.Levels[0].Color != DefaultLevelColor {
+		t.Errorf("unexpected levels: %+v", c.Levels)
+	}
+}
+
+func TestReadRaw(t *testing.T) {
+	f, err := ioutil.TempFile("", "octolog-*.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("loggername: ${APP:-main}\ndefaultformat: '{{.Message}}'\n")
+	f.Close()
+	os.Setenv("CONFIGOR_LOGGERNAME", "configor")
+	os.Setenv(EnvPrefix+"DEFAULT_FORMAT", "{{.Level}}")
+	defer os.Unsetenv("CONFIGOR_LOGGERNAME")
+	defer os.Unsetenv(EnvPrefix + "DEFAULT_FORMAT")
+
+	c, err := ReadRaw(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.LoggerName != "${APP:-main}" || c.DefaultFormat != "{{.Message}}" {
+		t.Errorf("expected the values as written, got %q and %q", c.LoggerName, c.DefaultFormat)
+	}
+	if c, err = Read(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	if c.LoggerName != "main" || c.DefaultFormat != "{{.Level}}" {
+		t.Errorf("expected interpolated and overridden values, got %q and %q", c.LoggerName, c.DefaultFormat)
+	}
+}
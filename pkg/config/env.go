@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the
+// loaded configuration:
+//
+//	OCTOLOG_DEFAULT_FORMAT        default log-format
+//	OCTOLOG_OUTPUTS               comma separated default outputs
+//	OCTOLOG_LOGGER_<NAME>_WANTS   comma separated log-levels of a logger
+//	OCTOLOG_LOGGER_<NAME>_OUTPUTS comma separated outputs of a logger
+//
+// <NAME> is the name of the logger in upper case, with every character other
+// than letters and digits replaced by an underscore. An empty list of wants
+// implies 'all'.
+const EnvPrefix = "OCTOLOG_"
+
+// Interpolate replaces ${VAR} and ${VAR:-default} in all strings of this
+// configuration with the value of the environment variable VAR. With the
+// second form, default is used if VAR is unset or empty.
+func (c *Config) Interpolate() {
+	interpolate(reflect.ValueOf(c).Elem())
+}
+
+func interpolate(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(Expand(v.String()))
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			interpolate(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				interpolate(v.Field(i))
+			}
+		}
+	}
+}
+
+// Expand replaces ${VAR} and ${VAR:-default} in the given string with the
+// value of the environment variable VAR.
+// Unterminated references are left as they are.
+func Expand(s string) string {
+	var out strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		out.WriteString(s[:start])
+		name, fallback := s[start+2:start+end], ""
+		if i := strings.Index(name, ":-"); i >= 0 {
+			name, fallback = name[:i], name[i+2:]
+		}
+		if value := os.Getenv(name); value != "" {
+			out.WriteString(value)
+		} else {
+			out.WriteString(fallback)
+		}
+		s = s[start+end+1:]
+	}
+	out.WriteString(s)
+	return out.String()
+}
+
+// ApplyEnv overrides this configuration with the environment variables
+// described by EnvPrefix. Loggers that are only configured by environment
+// variables are added under the upper case name given there.
+func (c *Config) ApplyEnv() {
+	if format := os.Getenv(EnvPrefix + "DEFAULT_FORMAT"); format != "" {
+		c.DefaultFormat = format
+	}
+	if outputs := splitList(os.Getenv(EnvPrefix + "OUTPUTS")); len(outputs) > 0 {
+		c.DefaultOutputs = outputs
+	}
+	prefix := EnvPrefix + "LOGGER_"
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 || !strings.HasPrefix(kv[0], prefix) {
+			continue
+		}
+		key := kv[0][len(prefix):]
+		switch {
+		case strings.HasSuffix(key, "_WANTS"):
+			logger := c.envLogger(strings.TrimSuffix(key, "_WANTS"))
+			logger.Wants = splitList(kv[1])
+		case strings.HasSuffix(key, "_OUTPUTS"):
+			logger := c.envLogger(strings.TrimSuffix(key, "_OUTPUTS"))
+			logger.Outputs = splitList(kv[1])
+		}
+	}
+}
+
+// envLogger returns the configured logger matching the given name of an
+// environment variable, adding it if necessary.
+func (c *Config) envLogger(name string) *Logger {
+	for i := range c.Loggers {
+		if EnvName(c.Loggers[i].Name) == name {
+			return &c.Loggers[i]
+		}
+	}
+	c.Loggers = append(c.Loggers, Logger{Name: name})
+	return &c.Loggers[len(c.Loggers)-1]
+}
+
+// EnvName returns the given logger-name as used in environment variables.
+func EnvName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// splitList returns the non-empty elements of the given comma separated list.
+func splitList(s string) []string {
+	var list []string
+	for _, elem := range strings.Split(s, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			list = append(list, elem)
+		}
+	}
+	return list
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	os.Setenv("OCTOLOG_TEST_DIR", "/var/log")
+	defer os.Unsetenv("OCTOLOG_TEST_DIR")
+	for in, expected := range map[string]string{
+		"file://${OCTOLOG_TEST_DIR}/app.log":       "file:///var/log/app.log",
+		"${OCTOLOG_TEST_UNSET:-json}":              "json",
+		"${OCTOLOG_TEST_DIR:-/tmp}":                "/var/log",
+		"${OCTOLOG_TEST_UNSET}":                    "",
+		"{{.Date}} ${OCTOLOG_TEST_DIR} ${unclosed": "{{.Date}} /var/log ${unclosed",
+	} {
+		if got := Expand(in); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"OCTOLOG_DEFAULT_FORMAT":             "json",
+		"OCTOLOG_OUTPUTS":                    "file:///dev/stdout, file:///dev/stderr",
+		"OCTOLOG_LOGGER_MY_APP_WANTS":        "ERROR",
+		"OCTOLOG_LOGGER_WORKER_OUTPUTS":      "file:///dev/stderr",
+		"OCTOLOG_TEST_LOGGER_URL_IN_CONFIG":  "file:///dev/null",
+		"OCTOLOG_LOGGER_MY_APP_UNKNOWN_FLAG": "ignored",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+	c := &Config{
+		DefaultFormat: "{{.Message}}",
+		Loggers: []Logger{{
+			Name:    "my-app",
+			Wants:   []string{"INFO"},
+			Outputs: []string{"${OCTOLOG_TEST_LOGGER_URL_IN_CONFIG}"},
+		}},
+	}
+	c.Interpolate()
+	c.ApplyEnv()
+
+	expected := &Config{
+		DefaultFormat:  "json",
+		DefaultOutputs: []string{"file:///dev/stdout", "file:///dev/stderr"},
+		Loggers: []Logger{{
+			Name:    "my-app",
+			Wants:   []string{"ERROR"},
+			Outputs: []string{"file:///dev/null"},
+		}, {
+			Name:    "WORKER",
+			Outputs: []string{"file:///dev/stderr"},
+		}},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("expected %+v, got %+v", expected, c)
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// levelsSource is the template of the Go source file returned by
+// GetLevelsSource.
+var levelsSource = template.Must(template.New("levels").Parse(`// Code generated by octolog gen-levels from {{.File}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+
+	"github.com/octogo/log/pkg/color"
+	"github.com/octogo/log/pkg/level"
+	octolog "github.com/octogo/log/pkg/log"
+)
+
+// Custom log-levels, registered by name upon initialization. Their numbers
+// depend on the log-levels registered before.
+var (
+{{- range .Levels}}
+	{{.Var}} = register({{printf "%q" .Name}}, {{printf "%q" .Color}})
+{{- end}}
+)
+
+// register registers the given log-level with the given color, or sets its
+// color if it is registered already, and returns it.
+func register(name, col string) level.Level {
+	seq, err := color.Parse(col)
+	if err != nil {
+		seq = color.NewLiteral(col)
+	}
+	lvl, _, err := level.Register(name, seq)
+	if err != nil {
+		panic(fmt.Sprintf("log-level %s: %v", name, err))
+	}
+	return lvl
+}
+
+// Logger wraps a *log.Logger with methods for the custom log-levels.
+type Logger struct {
+	*octolog.Logger
+}
+
+// Wrap returns a Logger with methods for the custom log-levels that logs
+// through the given Logger.
+func Wrap(logger *octolog.Logger) Logger {
+	return Logger{logger}
+}
+{{range .Levels}}
+// {{.Method}} logs the given value with log-level {{.Name}}.
+func (l Logger) {{.Method}}(v interface{}) {
+	l.WithCallerSkip(1).Log({{.Var}}, v)
+}
+
+// {{.Method}}f logs the given values with log-level {{.Name}} after formatting them.
+func (l Logger) {{.Method}}f(format string, args ...interface{}) {
+	l.WithCallerSkip(1).Logf({{.Var}}, format, args...)
+}
+{{end}}`))
+
+// sourceLevel is a custom log-level of a file generated by GetLevelsSource.
+type sourceLevel struct {
+	Name, Color string
+	Var, Method string
+}
+
+// GetLevelsSource returns a Go source file for the given package with
+// variables for the given custom log-levels, loaded from the given file, and
+// a Logger wrapper with methods logging with them, e.g. Audit and Auditf.
+// The levels are registered by name upon initialization, so other packages
+// may register log-levels before. Built-in levels are skipped.
+func GetLevelsSource(pkg, file string, levels []Level) (string, error) {
+	if pkg == "" {
+		pkg = "levels"
+	}
+	data := struct {
+		File, Package string
+		Levels        []sourceLevel
+	}{File: file, Package: pkg}
+	vars := map[string]string{}
+	for _, lvl := range levels {
+		if _, err := level.Parse(lvl.Name); err == nil {
+			continue
+		}
+		name := strings.ToUpper(lvl.Name)
+		c := sourceLevel{
+			Name:   name,
+			Color:  lvl.Color,
+			Var:    EnvName(name),
+			Method: methodName(name),
+		}
+		if c.Var == "" || c.Var[0] >= '0' && c.Var[0] <= '9' {
+			return "", fmt.Errorf("log-level %q is no valid Go identifier", lvl.Name)
+		}
+		if other, ok := vars[c.Var]; ok {
+			return "", fmt.Errorf("log-levels %q and %q map to the same identifier %s", other, lvl.Name, c.Var)
+		}
+		vars[c.Var] = lvl.Name
+		data.Levels = append(data.Levels, c)
+	}
+	if len(data.Levels) == 0 {
+		return "", fmt.Errorf("no custom log-levels in %s", file)
+	}
+	buf := new(bytes.Buffer)
+	if err := levelsSource.Execute(buf, data); err != nil {
+		return "", err
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// methodName returns the given log-level name in camel case, e.g. Audit for
+// AUDIT or SecurityAlert for SECURITY-ALERT.
+func methodName(name string) string {
+	words := strings.FieldsFunc(EnvName(name), func(r rune) bool { return r == '_' })
+	for i := range words {
+		words[i] = words[i][:1] + strings.ToLower(words[i][1:])
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGetLevelsSource(t *testing.T) {
+	src, err := GetLevelsSource("levels", "logging.yml", []Level{
+		{Name: "error", Color: "red"},
+		{Name: "audit", Color: "1;34"},
+		{Name: "security-alert", Color: "magenta"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "levels.go", src, 0); err != nil {
+		t.Fatalf("%v:\n%s", err, src)
+	}
+	for _, expected := range []string{
+		`AUDIT          = register("AUDIT", "1;34")`,
+		`SECURITY_ALERT = register("SECURITY-ALERT", "magenta")`,
+		"func (l Logger) SecurityAlert(v interface{}) {",
+		"func (l Logger) Auditf(format string, args ...interface{}) {",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("expected %q in:\n%s", expected, src)
+		}
+	}
+	if strings.Contains(src, "ERROR") {
+		t.Errorf("expected built-in levels to be skipped:\n%s", src)
+	}
+
+	for _, levels := range [][]Level{
+		nil,
+		{{Name: "1st"}},
+		{{Name: "a-b"}, {Name: "a_b"}},
+	} {
+		if _, err := GetLevelsSource("levels", "logging.yml", levels); err == nil {
+			t.Errorf("expected an error for %v", levels)
+		}
+	}
+}
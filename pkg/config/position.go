@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Line returns the line of the value at the given path (e.g.
+// outputs[2].wants[0]) in the given YAML or JSON document. If the value is
+// missing, the line of its closest parent is returned, or 0 if not even the
+// first key of the path is present.
+func Line(data []byte, path string) int {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0
+	}
+	node, line := doc.Content[0], 0
+	for _, step := range splitPath(path) {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		next := child(node, step)
+		if next == nil {
+			break
+		}
+		node, line = next, next.Line
+	}
+	return line
+}
+
+// splitPath returns the keys and indices of the given path as strings.
+func splitPath(path string) []string {
+	var steps []string
+	for _, part := range strings.Split(path, ".") {
+		for {
+			i := strings.IndexByte(part, '[')
+			if i < 0 {
+				break
+			}
+			if i > 0 {
+				steps = append(steps, part[:i])
+			}
+			j := strings.IndexByte(part, ']')
+			if j < i {
+				break
+			}
+			steps = append(steps, part[i:j+1])
+			part = part[j+1:]
+		}
+		if part != "" {
+			steps = append(steps, part)
+		}
+	}
+	return steps
+}
+
+// child returns the value of the given node at the given key or [index].
+func child(node *yaml.Node, step string) *yaml.Node {
+	if strings.HasPrefix(step, "[") {
+		i, err := strconv.Atoi(step[1 : len(step)-1])
+		if err != nil || node.Kind != yaml.SequenceNode || i < 0 || i >= len(node.Content) {
+			return nil
+		}
+		return node.Content[i]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, step) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
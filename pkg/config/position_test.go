@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestLine(t *testing.T) {
+	data := []byte(`
+defaultformat: '{{.Message}}'
+outputs:
+  - url: 'file:///dev/stdout'
+    wants: [INFO, WARN]
+  - url: 'file:///dev/stderr'
+    wants:
+      - ERROR
+      - CRIT
+loggers:
+  - name: main
+    sampling:
+      by: level
+`)
+	for path, expected := range map[string]int{
+		"defaultformat":          2,
+		"outputs[0].wants[1]":    5,
+		"outputs[1].wants[1]":    9,
+		"loggers[0].sampling.by": 13,
+		"loggers[0].dedup":       11,
+		"defaultoutputs[0]":      0,
+	} {
+		if got := Line(data, path); got != expected {
+			t.Errorf("%s: expected %d, got %d", path, expected, got)
+		}
+	}
+}
@@ -8,9 +8,14 @@ import (
 
 // SampleConfig holds the sample configuration file.
 var SampleConfig = `
-# rootlogger defines the name of the standard logger
-# default: main
-rootlogger: 'main'
+# Any value may reference environment variables as ${VAR} or
+# ${VAR:-default}, e.g. url: 'file://${LOG_DIR:-/var/log}/app.log'.
+# The variables OCTOLOG_DEFAULT_FORMAT, OCTOLOG_OUTPUTS and
+# OCTOLOG_LOGGER_<NAME>_WANTS override the values in this file.
+
+# loggername defines the name of the standard logger
+# default: octolog
+loggername: 'main'
 
 # default format defines the default log-format for all
 # outputs that habe no expicit log-format in the
@@ -30,12 +35,20 @@ rootlogger: 'main'
 # {{.Func}}       - name of the calling function
 # {{.File}}       - source file of the calling function
 # {{.Line}}       - line in the above source file
+# {{.Stack}}      - stack trace (see 'stacktrace' of outputs below)
+# {{.Err}}        - message of a logged error
+# {{.ErrorChain}} - wrapped errors, each with .Type and .Message
+# {{.ErrorStack}} - stack trace attached to a logged error
+# {{.Fields}}     - fields attached to a logged error
 #
 # supported colorize labels:
 # {{.Color}}      - activates coloring
 # {{.BoldColor}}  - activates bold coloring
 # {{.NoColor}}    - deactivates coloring
 #
+# Instead of a template, the structured encodings 'json' and 'logfmt'
+# can be used as format, too.
+#
 # default: '{{.Date}} {{.Time}} {{.Level}} {{.Message}}'
 defaultformat: '{{.Date}} {{.Time}} {{.BoldColor}}{{.Logger}} {{.Level}}{{.NoColor}} {{.Color}}{{.Message}}{{.NoColor}}'
 
@@ -56,6 +69,9 @@ defaultoutputs:
 #   - magenta
 #   - cyan
 #   - white
+# or their bright variants, such as bright-red, any 24-bit color, such as
+# '#ff8800', or any color of the 256-color palette, such as color(208).
+# Colors are degraded to what the terminal supports.
 # Any other value will be handled like a literal ANSII escape sequence, such as
 #   - 30;41   # (black text on red background)
 #   - 5;31    # blinking red text
@@ -83,6 +99,10 @@ levels:
 #     wants:    # the list of log-levels to log
 #               # providing no log-levels implies 'all'
 #     format:   # log-format to use, if not the global default
+#     stacktrace: # log-level from which on stack traces are logged,
+#               # i.e. ERROR or WARNING (default: none)
+#     dedup:    # collapse repeated messages into a summary that is logged
+#               # latest after the given duration, i.e. 10s (default: off)
 #   }
 outputs:
   # log INFO and NOTICE to STDOUT
@@ -91,6 +111,7 @@ outputs:
   # log WARNING and ERROR to STDERR
   - url: 'file:///dev/stderr'
     wants: [ WARNING, ERROR ]
+    stacktrace: ERROR
 
 # loggers defines the loggers that should automatically be
 # initialized upon startup.
@@ -102,6 +123,12 @@ outputs:
 #               # providing no log-levels implies 'all'
 #     outputs:  # list of output URLs to communicate with
 #               # providing no URLs implies 'defaultoutputs'
+#     sampling: # optionally limits the number of entries per interval
+#       interval:   # i.e. 1s (default)
+#       first:      # number of entries logged per interval
+#       thereafter: # log every n-th entry after the first ones
+#       by:         # group entries by 'message' (default) or 'caller'
+#     dedup:    # collapse repeated messages, see outputs above
 #   }
 loggers:
   - name: main
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaURI identifies the JSON Schema draft returned by Schema.
+const SchemaURI = "http://json-schema.org/draft-07/schema#"
+
+// Schema returns the JSON Schema of configuration files, generated from
+// Config. Keys are the lower case field names, as expected in YAML files.
+//
+// The schema tag of a field marks it as required or restricts its values:
+//
+//	schema:"required"
+//	schema:"minimum=0"
+//	schema:"enum=message|caller"
+func Schema() map[string]interface{} {
+	schema := schemaOf(reflect.TypeOf(Config{}))
+	schema["$schema"] = SchemaURI
+	schema["title"] = "octolog configuration"
+	return schema
+}
+
+func schemaOf(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Ptr:
+		return schemaOf(t.Elem())
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := strings.ToLower(field.Name)
+			property := schemaOf(field.Type)
+			if description := field.Tag.Get("description"); description != "" {
+				property["description"] = description
+			}
+			if def, ok := field.Tag.Lookup("default"); ok {
+				property["default"] = schemaValue(field.Type, def)
+			}
+			for _, rule := range strings.Split(field.Tag.Get("schema"), ",") {
+				kv := strings.SplitN(rule, "=", 2)
+				switch kv[0] {
+				case "required":
+					required = append(required, name)
+				case "minimum":
+					property["minimum"] = schemaValue(field.Type, kv[1])
+				case "enum":
+					values := strings.Split(kv[1], "|")
+					enum := make([]interface{}, len(values))
+					for j := range values {
+						enum[j] = schemaValue(field.Type, values[j])
+					}
+					property["enum"] = enum
+				}
+			}
+			properties[name] = property
+		}
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaValue returns the given tag value as a value of the given type.
+// Strings are decoded as YAML, just like defaults are by configor.
+func schemaValue(t reflect.Type, value string) interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		var s string
+		if err := yaml.Unmarshal([]byte(value), &s); err == nil {
+			return s
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	properties := Schema()["properties"].(map[string]interface{})
+	for _, key := range []string{"defaultformat", "loggername", "defaultoutputs", "levels", "outputs", "loggers"} {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("expected property %q in %v", key, properties)
+		}
+	}
+	format := properties["defaultformat"].(map[string]interface{})
+	if expected := "{{.Date}} {{.Time}} {{.Logger}} {{.Level}} {{.Message}}"; format["default"] != expected {
+		t.Errorf("expected %q, got %v", expected, format["default"])
+	}
+	loggers := properties["loggers"].(map[string]interface{})["items"].(map[string]interface{})
+	if required := loggers["required"].([]string); len(required) != 1 || required[0] != "name" {
+		t.Errorf("expected %v, got %v", []string{"name"}, required)
+	}
+	sampling := loggers["properties"].(map[string]interface{})["sampling"].(map[string]interface{})
+	by := sampling["properties"].(map[string]interface{})["by"].(map[string]interface{})
+	if enum := by["enum"].([]interface{}); len(enum) != 2 {
+		t.Errorf("expected %v, got %v", []string{"message", "caller"}, enum)
+	}
+}
+
+func TestReadStrict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "octolog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sample := filepath.Join(dir, "sample.yml")
+	if err := ioutil.WriteFile(sample, []byte(GetSampleConfig("test")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadE(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.LoggerName != "main" {
+		t.Errorf("expected %v, got %v", "main", c.LoggerName)
+	}
+
+	misspelled := filepath.Join(dir, "misspelled.yml")
+	if err := ioutil.WriteFile(misspelled, []byte("rootlogger: main\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(misspelled); err == nil || !strings.Contains(err.Error(), "rootlogger") {
+		t.Errorf("expected an error about rootlogger, got %v", err)
+	}
+}
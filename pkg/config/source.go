@@ -0,0 +1,92 @@
+package config
+
+import (
+	"go/format"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// sourceTemplate is the Go source file returned by GetSource.
+var sourceTemplate = `// Code generated by octolog gensrc from %FILE%; DO NOT EDIT.
+
+package %PKG%
+
+import (
+	"github.com/octogo/log"
+	"github.com/octogo/log/pkg/config"
+)
+
+var octologConfig = %CONFIG%
+
+func initOctolog() {
+	octologConfig.Interpolate()
+	octologConfig.ApplyEnv()
+	log.InitWithConfig(octologConfig)
+}
+`
+
+// GetSource returns a Go source file for the given package that builds the
+// given configuration, loaded from the given file, as config.Config literal.
+// Just like the configuration file, the literal is interpolated and
+// overridden by environment variables upon initialization, so it should be
+// read by ReadRaw.
+func GetSource(pkg, file string, c *Config) (string, error) {
+	if pkg == "" {
+		pkg = "main"
+	}
+	src := strings.NewReplacer(
+		"%FILE%", file,
+		"%PKG%", pkg,
+		"%CONFIG%", "&"+literal(reflect.ValueOf(*c), true),
+	).Replace(sourceTemplate)
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// literal returns the given value as Go literal. Struct literals are typed
+// unless their type is implied, e.g. by the type of a slice.
+func literal(v reflect.Value, typed bool) string {
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Slice:
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elems[i] = literal(v.Index(i), false)
+		}
+		return "[]" + typeName(v.Type().Elem()) + "{\n" + strings.Join(elems, ",\n") + ",\n}"
+	case reflect.Struct:
+		var fields []string
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+				continue
+			}
+			fields = append(fields, v.Type().Field(i).Name+": "+literal(field, true))
+		}
+		lit := "{}"
+		if len(fields) > 0 {
+			lit = "{\n" + strings.Join(fields, ",\n") + ",\n}"
+		}
+		if typed {
+			return typeName(v.Type()) + lit
+		}
+		return lit
+	}
+	panic("unsupported type in configuration: " + v.Type().String())
+}
+
+// typeName returns the name of the given type as referred to in other
+// packages.
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Struct {
+		return "config." + t.Name()
+	}
+	return t.Name()
+}
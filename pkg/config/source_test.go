@@ -0,0 +1,47 @@
+package config
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "octolog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "logging.yml")
+	yml := "loggername: ${APP:-main}\nlevels:\n  - name: audit\noutputs:\n  - url: file:///dev/stdout\n    wants: [audit, info]\nloggers:\n  - name: db\n    sampling:\n      first: 10\n"
+	if err := ioutil.WriteFile(file, []byte(yml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ReadRaw(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := GetSource("app", "logging.yml", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "log.go", src, 0); err != nil {
+		t.Fatalf("%v:\n%s", err, src)
+	}
+	for _, expected := range []string{
+		"package app\n",
+		`LoggerName:    "${APP:-main}",`,
+		"Name:  \"audit\",\n\t\t\tColor: \"magenta\",",
+		"Wants: []string{\n\t\t\t\t\"audit\",",
+		"Sampling: config.Sampling{\n\t\t\t\tFirst: 10,",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("expected %q in:\n%s", expected, src)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/octogo/log/pkg/color"
+	"github.com/octogo/log/pkg/level"
+)
+
+// FieldError describes a problem with the value at the given path of a
+// configuration (e.g. outputs[2].wants[0]).
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Errors holds all problems found in a configuration.
+type Errors []*FieldError
+
+// Error returns the problems one per line.
+func (errs Errors) Error() string {
+	lines := make([]string, len(errs))
+	for i := range errs {
+		lines[i] = errs[i].Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Add records a problem with the value at the given path.
+func (errs *Errors) Add(path string, err error) {
+	*errs = append(*errs, &FieldError{Path: path, Err: err})
+}
+
+// Err returns these errors, or nil if there are none.
+func (errs Errors) Err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Validate returns Errors describing all problems of this configuration that
+// can be detected without knowing the supported outputs and formats.
+// Level-names may refer to registered levels or to levels defined by this
+// configuration.
+func (c *Config) Validate() error {
+	var errs Errors
+	levels := map[string]bool{}
+	for i, lvl := range c.Levels {
+		path := fmt.Sprintf("levels[%d]", i)
+		name := strings.ToUpper(lvl.Name)
+		switch {
+		case name == "":
+			errs.Add(path+".name", errors.New("missing name"))
+		case levels[name]:
+			errs.Add(path+".name", fmt.Errorf("duplicate log-level %q", lvl.Name))
+		}
+		levels[name] = true
+		if lvl.Color == "" {
+			continue
+		}
+		if _, err := color.Parse(lvl.Color); err != nil {
+			errs.Add(path+".color", err)
+		}
+	}
+	checkLevel := func(path, name string) {
+		if !levels[strings.ToUpper(name)] && !level.IsValidName(name) {
+			errs.Add(path, fmt.Errorf("undefined log-level %q", name))
+		}
+	}
+	checkDuration := func(path, d string) {
+		if d == "" {
+			return
+		}
+		if parsed, err := time.ParseDuration(d); err != nil {
+			errs.Add(path, err)
+		} else if parsed < 0 {
+			errs.Add(path, fmt.Errorf("negative duration %q", d))
+		}
+	}
+
+	urls := map[string]bool{}
+	for i, out := range c.Outputs {
+		path := fmt.Sprintf("outputs[%d]", i)
+		switch {
+		case out.URL == "":
+			errs.Add(path+".url", errors.New("missing URL"))
+		case urls[out.URL]:
+			errs.Add(path+".url", fmt.Errorf("duplicate output %q", out.URL))
+		}
+		urls[out.URL] = true
+		for j := range out.Wants {
+			checkLevel(fmt.Sprintf("%s.wants[%d]", path, j), out.Wants[j])
+		}
+		if out.Stacktrace != "" {
+			checkLevel(path+".stacktrace", out.Stacktrace)
+		}
+		checkDuration(path+".dedup", out.Dedup)
+	}
+
+	names := map[string]bool{}
+	for i, logger := range c.Loggers {
+		path := fmt.Sprintf("loggers[%d]", i)
+		switch {
+		case logger.Name == "":
+			errs.Add(path+".name", errors.New("missing name"))
+		case names[logger.Name]:
+			errs.Add(path+".name", fmt.Errorf("duplicate logger %q", logger.Name))
+		}
+		names[logger.Name] = true
+		for j := range logger.Wants {
+			checkLevel(fmt.Sprintf("%s.wants[%d]", path, j), logger.Wants[j])
+		}
+		for j := range logger.Outputs {
+			if logger.Outputs[j] == "" {
+				errs.Add(fmt.Sprintf("%s.outputs[%d]", path, j), errors.New("missing URL"))
+			}
+		}
+		checkDuration(path+".sampling.interval", logger.Sampling.Interval)
+		if logger.Sampling.First < 0 {
+			errs.Add(path+".sampling.first", fmt.Errorf("negative number %d", logger.Sampling.First))
+		}
+		if logger.Sampling.Thereafter < 0 {
+			errs.Add(path+".sampling.thereafter", fmt.Errorf("negative number %d", logger.Sampling.Thereafter))
+		}
+		switch strings.ToLower(logger.Sampling.By) {
+		case "", "message", "caller":
+		default:
+			errs.Add(path+".sampling.by", fmt.Errorf("expected message or caller, got %q", logger.Sampling.By))
+		}
+		checkDuration(path+".dedup", logger.Dedup)
+	}
+	return errs.Err()
+}
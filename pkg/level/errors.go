@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	errLevelUndefined = errors.New("undefined log-level")
+	errTooManyLevels  = errors.New("too many log-levels")
 )
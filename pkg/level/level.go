@@ -1,6 +1,7 @@
 package level
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 
@@ -26,26 +27,31 @@ const (
 	DEBUG
 )
 
-var (
-	registeredLevels = map[string]Level{
+var registeredLevels, registeredColorSequences = builtinLevels()
+
+// builtinLevels returns the built-in log-levels and their colors.
+func builtinLevels() (map[string]Level, map[Level]color.Sequence) {
+	levels := map[string]Level{
 		"ERROR":   ERROR,
 		"WARNING": WARNING,
 		"NOTICE":  NOTICE,
 		"INFO":    INFO,
 		"DEBUG":   DEBUG,
 	}
-	registeredColorSequences = map[Level]color.Sequence{
+	colors := map[Level]color.Sequence{
 		ERROR:   color.New(color.NormalDisplay, color.Red),
 		WARNING: color.New(color.NormalDisplay, color.Yellow),
 		NOTICE:  color.New(color.NormalDisplay, color.Green),
 		INFO:    color.New(color.NormalDisplay, color.White),
 		DEBUG:   color.New(color.NormalDisplay, color.Cyan),
 	}
-)
+	return levels, colors
+}
 
 var mu = &sync.Mutex{}
 
-// String implements fmt.Stringer
+// String implements fmt.Stringer. Log-levels that are not registered are
+// rendered as LEVEL(n).
 func (lvl Level) String() string {
 	mu.Lock()
 	defer mu.Unlock()
@@ -54,10 +60,11 @@ func (lvl Level) String() string {
 			return k
 		}
 	}
-	panic("level not registered")
+	return fmt.Sprintf("LEVEL(%d)", lvl)
 }
 
 // Register registers a new log-level under the given name.
+// At most 256 log-levels can be registered.
 func Register(name string, colSeq color.Sequence) (Level, bool, error) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -68,11 +75,23 @@ func Register(name string, colSeq color.Sequence) (Level, bool, error) {
 			return registeredLevels[k], false, nil
 		}
 	}
+	if len(registeredLevels) > int(^Level(0)) {
+		return 0, false, errTooManyLevels
+	}
 	registeredLevels[name] = Level(len(registeredLevels))
 	registeredColorSequences[registeredLevels[name]] = colSeq
 	return registeredLevels[name], true, nil
 }
 
+// Reset unregisters all but the built-in log-levels and restores their
+// colors. Custom log-levels obtained before are rendered as LEVEL(n)
+// afterwards.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	registeredLevels, registeredColorSequences = builtinLevels()
+}
+
 // Levels returns a []Level of all registered levels.
 func Levels() []Level {
 	mu.Lock()
@@ -1,6 +1,7 @@
 package level
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/octogo/log/pkg/color"
@@ -75,3 +76,32 @@ func TestParse(t *testing.T) {
 		t.Errorf("expected %v, got %v", ERROR, level)
 	}
 }
+
+func TestReset(t *testing.T) {
+	custom, _, _ := Register("TEST-RESET", color.New(color.NormalDisplay, color.Blue))
+	Register("ERROR", color.New(color.NormalDisplay, color.Blue))
+	Reset()
+	if IsValidName("TEST-RESET") {
+		t.Errorf("expected %v, got %v", false, true)
+	}
+	if expected := fmt.Sprintf("LEVEL(%d)", custom); custom.String() != expected {
+		t.Errorf("expected %q, got %q", expected, custom.String())
+	}
+	if expected := color.New(color.NormalDisplay, color.Red); ERROR.Color().String() != expected.String() {
+		t.Errorf("expected %q, got %q", expected, ERROR.Color())
+	}
+}
+
+func TestRegisterTooMany(t *testing.T) {
+	defer Reset()
+	var err error
+	for i := len(Levels()); i <= 256 && err == nil; i++ {
+		_, _, err = Register(fmt.Sprintf("too-many-%d", i), nil)
+	}
+	if err != errTooManyLevels {
+		t.Errorf("expected %v, got %v", errTooManyLevels, err)
+	}
+	if len(Levels()) != 256 {
+		t.Errorf("expected %v, got %v", 256, len(Levels()))
+	}
+}
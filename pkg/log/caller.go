@@ -0,0 +1,234 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+// callerSkip is the number of stack frames between runtime.Callers in
+// Logger.frames and the function that called one of the Logger's methods.
+const callerSkip = 5
+
+// callerFields are the fields of an Entry holding caller information.
+var callerFields = []string{"Func", "File", "Line"}
+
+// maxCallerDepth limits the number of frames inspected while skipping helpers.
+const maxCallerDepth = 32
+
+// maxStackDepth limits the number of frames recorded in stack traces.
+const maxStackDepth = 64
+
+var (
+	helpers   = map[string]bool{}
+	helpersMu = &sync.RWMutex{}
+)
+
+// Helper marks the calling function as a logging helper. When reporting the
+// caller of an entry, frames of helper functions are skipped for all Loggers.
+// Wrapper packages call Helper at the top of every function that logs on
+// behalf of its own caller, just like testing.T.Helper.
+func Helper() {
+	if name := callerName(); name != "" {
+		helpersMu.Lock()
+		helpers[name] = true
+		helpersMu.Unlock()
+	}
+}
+
+// Helper marks the calling function as a logging helper of this Logger.
+// When reporting the caller of an entry logged by this Logger (or any Logger
+// derived from it by WithCallerSkip), frames of helper functions are skipped.
+func (l *Logger) Helper() {
+	if name := callerName(); name != "" {
+		o := l.origin()
+		o.mu.Lock()
+		o.helpers[name] = true
+		o.mu.Unlock()
+	}
+}
+
+// WithCallerSkip returns a Logger that logs through l, but reports the caller
+// n stack frames further up. The returned Logger shares its name, wants,
+// outputs and counters with l.
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	derived := l.derive()
+	derived.skip += n
+	return derived
+}
+
+// derive returns a Logger that logs through the origin of l with the caller
+// skip, error and fields of l. It copies only those fields of l that never
+// change, so it does not need to hold the lock of the origin.
+func (l *Logger) derive() *Logger {
+	o := l.origin()
+	return &Logger{
+		Name:    o.Name,
+		uid:     o.uid,
+		mu:      o.mu,
+		helpers: o.helpers,
+		base:    o,
+		skip:    l.skip,
+		err:     l.err,
+		fields:  l.fields,
+	}
+}
+
+// origin returns the Logger that l has been derived from or l itself.
+func (l *Logger) origin() *Logger {
+	if l.base != nil {
+		return l.base
+	}
+	return l
+}
+
+// caller returns the function, file and line of the first frame outside of
+// octolog and any registered helpers. The caller must hold l.origin().mu.
+func (l *Logger) caller() (function, file string, line int) {
+	frames := l.frames(maxCallerDepth)
+	if len(frames) == 0 {
+		return
+	}
+	return frames[0].Function, frames[0].File, frames[0].Line
+}
+
+// stack returns the stack trace of the goroutine calling the Logger, trimmed
+// of runtime and octolog frames. The caller must hold l.origin().mu.
+func (l *Logger) stack() string {
+	frames := l.frames(maxStackDepth)
+	lines := make([]string, 0, 2*len(frames))
+	for i := range frames {
+		if strings.HasPrefix(frames[i].Function, "runtime.") {
+			continue
+		}
+		lines = append(
+			lines,
+			frames[i].Function+"()",
+			fmt.Sprintf("\t%s:%d", frames[i].File, frames[i].Line),
+		)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// frames returns up to depth frames of the calling goroutine, starting at the
+// first frame outside of octolog and any registered helpers.
+func (l *Logger) frames(depth int) []runtime.Frame {
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(callerSkip+l.skip, pcs)
+	if n == 0 {
+		return nil
+	}
+	o := l.origin()
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+	var (
+		out     []runtime.Frame
+		frames  = runtime.CallersFrames(pcs[:n])
+		leading = true
+	)
+	for {
+		frame, more := frames.Next()
+		if leading && more && (helpers[frame.Function] || o.helpers[frame.Function]) {
+			continue
+		}
+		leading = false
+		out = append(out, frame)
+		if !more {
+			return out
+		}
+	}
+}
+
+// callerName returns the name of the function calling the function that
+// calls callerName.
+func callerName() string {
+	pcs := make([]uintptr, 1)
+	if runtime.Callers(3, pcs) == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	return frame.Function
+}
+
+// callerUser is implemented by outputs that can tell whether they render
+// caller information. Outputs not implementing it are always handed entries
+// with caller information.
+type callerUser interface {
+	UsesCaller() bool
+}
+
+// usesCaller returns true if any of the given outputs renders caller
+// information.
+func usesCaller(outputs []Output) bool {
+	for i := range outputs {
+		user, ok := outputs[i].(callerUser)
+		if !ok || user.UsesCaller() {
+			return true
+		}
+	}
+	return false
+}
+
+// formatUses returns true if the given log-format references any of the given
+// fields of an Entry. Formats that fail to parse or that pass the whole Entry
+// to a function are assumed to use every field.
+func formatUses(format string, fields ...string) bool {
+	tmpl, err := template.New("octolog/uses").Parse(format)
+	if err != nil || tmpl.Tree == nil {
+		return true
+	}
+	wanted := make(map[string]bool, len(fields))
+	for i := range fields {
+		wanted[fields[i]] = true
+	}
+	return nodeUses(tmpl.Tree.Root, wanted)
+}
+
+func nodeUses(node parse.Node, fields map[string]bool) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for i := range n.Nodes {
+			if nodeUses(n.Nodes[i], fields) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return nodeUses(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for i := range n.Cmds {
+			if nodeUses(n.Cmds[i], fields) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for i := range n.Args {
+			if nodeUses(n.Args[i], fields) {
+				return true
+			}
+		}
+	case *parse.FieldNode:
+		return len(n.Ident) > 0 && fields[n.Ident[0]]
+	case *parse.ChainNode:
+		return nodeUses(n.Node, fields)
+	case *parse.DotNode:
+		return true
+	case *parse.IfNode:
+		return nodeUses(n.Pipe, fields) || nodeUses(n.List, fields) || nodeUses(n.ElseList, fields)
+	case *parse.RangeNode:
+		return nodeUses(n.Pipe, fields) || nodeUses(n.List, fields) || nodeUses(n.ElseList, fields)
+	case *parse.WithNode:
+		return nodeUses(n.Pipe, fields) || nodeUses(n.List, fields) || nodeUses(n.ElseList, fields)
+	case *parse.TemplateNode:
+		return nodeUses(n.Pipe, fields)
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func newCallerTestLogger(t *testing.T, name string) (*Logger, *os.File) {
+	f, err := ioutil.TempFile("", "octolog-caller")
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewFileOutput(f, nil, "{{.Func}}")
+	return NewLogger(name, nil, "file://"+f.Name()), f
+}
+
+func lastLine(t *testing.T, f *os.File) string {
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	return lines[len(lines)-1]
+}
+
+func wrappedInfo(l *Logger, v interface{}) {
+	l.WithCallerSkip(1).Info(v)
+}
+
+func helperInfo(l *Logger, v interface{}) {
+	l.Helper()
+	l.Info(v)
+}
+
+func TestLoggerCaller(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-CALLER")
+	defer os.Remove(f.Name())
+	expected := "github.com/octogo/log/pkg/log.TestLoggerCaller"
+
+	logger.Info("direct")
+	if got := lastLine(t, f); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	wrappedInfo(logger, "skipped")
+	if got := lastLine(t, f); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	helperInfo(logger, "helper")
+	if got := lastLine(t, f); got != expected {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFormatUses(t *testing.T) {
+	if formatUses(DefaultLogFormat, callerFields...) {
+		t.Errorf("expected %v, got %v", false, true)
+	}
+	if !formatUses(DefaultDebugFormat, callerFields...) {
+		t.Errorf("expected %v, got %v", true, false)
+	}
+	if !formatUses("{{if .Line}}{{.File}}{{end}}", callerFields...) {
+		t.Errorf("expected %v, got %v", true, false)
+	}
+}
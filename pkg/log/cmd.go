@@ -0,0 +1,165 @@
+package log
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// Command runs an exec.Cmd and logs every line of its output, see Cmd.
+type Command struct {
+	*exec.Cmd
+
+	// Logger logs the output, exit status and runtime of the command.
+	Logger *Logger
+	// StdoutLevel and StderrLevel are the log-levels of lines written to
+	// STDOUT and STDERR.
+	StdoutLevel level.Level
+	StderrLevel level.Level
+
+	started time.Time
+	done    sync.WaitGroup
+
+	// mu guards process and the signals forwarded before it was started.
+	mu      sync.Mutex
+	process *os.Process
+	pending []os.Signal
+}
+
+// Cmd returns a Command that logs every line the given command writes to
+// STDOUT with log-level INFO and to STDERR with log-level WARNING through
+// the given Logger (nil implies the standard logger). Once it exits, its
+// exit status is logged with log-level INFO, or ERROR if it failed, along
+// with the fields exit and runtime.
+//
+//	err := log.Cmd(exec.Command("legacy", "--serve"), logger).Run()
+func Cmd(cmd *exec.Cmd, logger *Logger) *Command {
+	if logger == nil {
+		logger = defaultLogger
+	}
+	return &Command{
+		Cmd:         cmd,
+		Logger:      logger,
+		StdoutLevel: level.INFO,
+		StderrLevel: level.WARNING,
+	}
+}
+
+// Run starts the command and waits for it to exit.
+func (c *Command) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// Start starts the command without waiting for it to exit.
+func (c *Command) Start() error {
+	if c.Logger == nil {
+		return errors.New("no logger to log the command with")
+	}
+	stdout, err := c.Cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := c.Cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	c.started = time.Now()
+	if err := c.Cmd.Start(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.process = c.Cmd.Process
+	for i := range c.pending {
+		c.process.Signal(c.pending[i])
+	}
+	c.pending = nil
+	c.mu.Unlock()
+	c.done.Add(2)
+	go c.logLines(stdout, c.StdoutLevel)
+	go c.logLines(stderr, c.StderrLevel)
+	return nil
+}
+
+// Wait waits for the command to exit, after logging all of its output, and
+// logs its exit status and runtime.
+func (c *Command) Wait() error {
+	c.done.Wait()
+	err := c.Cmd.Wait()
+	state := c.Cmd.ProcessState
+	if state == nil {
+		return err
+	}
+	logger := c.Logger.WithFields(map[string]interface{}{
+		"exit":    state.ExitCode(),
+		"runtime": time.Since(c.started).String(),
+	})
+	lvl := level.INFO
+	if !state.Success() {
+		lvl = level.ERROR
+	}
+	logger.Logf(lvl, "%s: %s", filepath.Base(c.Cmd.Path), state)
+	return err
+}
+
+// Forward forwards the given signals received by this process to the
+// command. Call it before Start so that no signal is missed: signals received
+// until the command has started are forwarded once it has. The returned
+// function stops forwarding them.
+func (c *Command) Forward(signals ...os.Signal) (stop func()) {
+	received := make(chan os.Signal, len(signals))
+	done := make(chan struct{})
+	signal.Notify(received, signals...)
+	go func() {
+		for {
+			select {
+			case sig := <-received:
+				c.signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(received)
+		close(done)
+	}
+}
+
+// signal sends the given signal to the command, or once it has started.
+func (c *Command) signal(sig os.Signal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.process == nil {
+		c.pending = append(c.pending, sig)
+		return
+	}
+	c.process.Signal(sig)
+}
+
+// logLines logs every non-empty line read from the given reader with the
+// given log-level.
+func (c *Command) logLines(r io.Reader, lvl level.Level) {
+	defer c.done.Done()
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			c.Logger.log(line, lvl)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestCmd(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	logger, f := newCallerTestLogger(t, "TEST-CMD")
+	defer os.Remove(f.Name())
+	GetOutput("file://" + f.Name()).SetFormat("{{.Level}} {{.Message}}")
+
+	cmd := Cmd(exec.Command("sh", "-c", "echo out; echo; echo err >&2; exit 3"), logger)
+	if err := cmd.Run(); err == nil {
+		t.Error("expected the exit status as error")
+	}
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected %v lines, got %q", 5, lines)
+	}
+	if !(lines[0] == "INFO out" && lines[1] == "WARNING err" || lines[0] == "WARNING err" && lines[1] == "INFO out") {
+		t.Errorf("unexpected output: %q", lines[:2])
+	}
+	if lines[2] != "ERROR sh: exit status 3" || lines[3] != "\texit=3" || !strings.HasPrefix(lines[4], "\truntime=") {
+		t.Errorf("unexpected exit entry: %q", lines[2:])
+	}
+}
+
+func TestCmdForward(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not found")
+	}
+	logger, f := newCallerTestLogger(t, "TEST-CMD-FORWARD")
+	defer os.Remove(f.Name())
+
+	cmd := Cmd(exec.Command("sleep", "10"), logger)
+	stop := cmd.Forward(syscall.SIGHUP)
+	defer stop()
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	waitFor(t, func() bool {
+		cmd.mu.Lock()
+		defer cmd.mu.Unlock()
+		return len(cmd.pending) == 1
+	})
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err == nil {
+		t.Fatal("expected the signal as error")
+	}
+	status := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !status.Signaled() || status.Signal() != syscall.SIGHUP {
+		t.Errorf("expected %v, got %v", syscall.SIGHUP, cmd.ProcessState)
+	}
+}
@@ -1,5 +1,7 @@
 package log
 
+import "sync"
+
 var (
 	// DefaultLogFormat is defined as
 	DefaultLogFormat = "{{.Date}} {{.Time}} {{.BoldColor}}{{.Logger}} {{.Level}}{{.NoColor}} {{.Color}}{{.Message}}{{.NoColor}}"
@@ -16,3 +18,21 @@ var (
 		"file:///dev/stderr",
 	}
 )
+
+// configMu guards DefaultLogFormat and DefaultOutputs against Reconfigure,
+// which also holds it while logging is paused.
+var configMu = &sync.RWMutex{}
+
+// defaults returns DefaultLogFormat and DefaultOutputs.
+func defaults() (format string, outputs []string) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return DefaultLogFormat, DefaultOutputs
+}
+
+// the initial values of the variables above, restored by Reset
+var (
+	initialLogFormat  = DefaultLogFormat
+	initialLoggerName = LoggerName
+	initialOutputs    = append([]string{}, DefaultOutputs...)
+)
@@ -0,0 +1,209 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decoder decodes the lines written by an output back into records.
+type Decoder interface {
+	Decode(line string) (*Record, error)
+}
+
+// NewDecoder returns the Decoder for the given structured encoding ("json" or
+// "logfmt"), or nil for any other format. With an empty format, the encoding
+// is detected per line.
+func NewDecoder(format string) Decoder {
+	switch strings.ToLower(format) {
+	case JSONFormat:
+		return jsonDecoder{}
+	case LogfmtFormat:
+		return logfmtDecoder{}
+	case "":
+		return structuredDecoder{}
+	default:
+		return nil
+	}
+}
+
+// errNotStructured is returned for lines that are not structured entries.
+var errNotStructured = errors.New("not a structured entry")
+
+type structuredDecoder struct{}
+
+// Decode decodes lines starting with '{' as JSON and any other line as
+// logfmt.
+func (structuredDecoder) Decode(line string) (*Record, error) {
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return jsonDecoder{}.Decode(line)
+	}
+	return logfmtDecoder{}.Decode(line)
+}
+
+type jsonDecoder struct{}
+
+// Decode decodes an entry encoded as JSON object. Numbers in fields are
+// decoded as json.Number.
+func (jsonDecoder) Decode(line string) (*Record, error) {
+	var s structured
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	if s.Level == "" || s.Message == "" && s.Time == "" {
+		return nil, errNotStructured
+	}
+	r := &Record{
+		Time:    parseTime(s.Time),
+		Level:   s.Level,
+		Logger:  s.Logger,
+		Message: s.Message,
+		GID:     s.GID,
+		LID:     s.LID,
+		PID:     s.PID,
+		Func:    s.Func,
+		File:    s.File,
+		Line:    s.Line,
+		Stack:   s.Stack,
+		Fields:  s.Fields,
+	}
+	if s.Error != nil {
+		r.ErrorChain = s.Error.Chain
+		if len(r.ErrorChain) == 0 {
+			r.ErrorChain = []ErrorInfo{{Message: s.Error.Message}}
+		}
+		r.ErrorStack = s.Error.Stack
+	}
+	return r, nil
+}
+
+type logfmtDecoder struct{}
+
+// Decode decodes an entry encoded as key=value pairs. Keys other than those
+// written by the logfmt encoding are decoded as fields.
+func (logfmtDecoder) Decode(line string) (*Record, error) {
+	pairs, err := parseLogfmt(line)
+	if err != nil {
+		return nil, err
+	}
+	r := &Record{}
+	var errMessage, errTypes string
+	for _, kv := range pairs {
+		key, value := kv[0], kv[1]
+		switch key {
+		case "time":
+			r.Time = parseTime(value)
+		case "level":
+			r.Level = value
+		case "logger":
+			r.Logger = value
+		case "message":
+			r.Message = value
+		case "gid":
+			r.GID, _ = strconv.ParseUint(value, 10, 64)
+		case "lid":
+			r.LID, _ = strconv.ParseUint(value, 10, 64)
+		case "pid":
+			r.PID, _ = strconv.Atoi(value)
+		case "func":
+			r.Func = value
+		case "file":
+			r.File = value
+		case "line":
+			r.Line, _ = strconv.Atoi(value)
+		case "stack":
+			r.Stack = value
+		case "error":
+			errMessage = value
+		case "error_types":
+			errTypes = value
+		case "error_stack":
+			r.ErrorStack = value
+		default:
+			if r.Fields == nil {
+				r.Fields = map[string]interface{}{}
+			}
+			r.Fields[key] = value
+		}
+	}
+	if r.Level == "" || r.Message == "" && r.Time.IsZero() {
+		return nil, errNotStructured
+	}
+	if errMessage != "" || errTypes != "" {
+		types := strings.Split(errTypes, ",")
+		r.ErrorChain = make([]ErrorInfo, len(types))
+		for i := range types {
+			r.ErrorChain[i].Type = types[i]
+		}
+		r.ErrorChain[0].Message = errMessage
+	}
+	return r, nil
+}
+
+// parseLogfmt returns the key=value pairs of the given line in their order.
+func parseLogfmt(line string) ([][2]string, error) {
+	var pairs [][2]string
+	line = strings.TrimSpace(line)
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsAny(line[:eq], " \t\"") {
+			return nil, errNotStructured
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := closingQuote(line)
+			if end < 0 {
+				return nil, errNotStructured
+			}
+			unquoted, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				return nil, err
+			}
+			value, line = unquoted, line[end+1:]
+		} else if end := strings.IndexAny(line, " \t"); end >= 0 {
+			value, line = line[:end], line[end:]
+		} else {
+			value, line = line, ""
+		}
+		pairs = append(pairs, [2]string{key, value})
+		line = strings.TrimLeft(line, " \t")
+	}
+	return pairs, nil
+}
+
+// closingQuote returns the index of the quote closing the quoted string at
+// the start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// timeLayouts are the layouts of the times written by the encodings.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006/01/02 15:04:05.000000",
+	"2006/01/02 15:04:05",
+}
+
+// parseTime returns the time written by an encoding, or the zero time.
+func parseTime(s string) time.Time {
+	for i := range timeLayouts {
+		if t, err := time.ParseInLocation(timeLayouts[i], s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
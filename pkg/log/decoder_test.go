@@ -0,0 +1,57 @@
+package log
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+func TestDecodeRoundTrip(t *testing.T) {
+	e := newEntry("TEST MESSAGE with \"quotes\"", testLogger, testEntry.LevelLevel(), "caller", "file", 42)
+	e.setError(wrappingError{"outer", errors.New("inner")})
+	e.fields = map[string]interface{}{"user": "bob", "attempt": 3}
+	for _, format := range []string{JSONFormat, LogfmtFormat} {
+		encoded := NewEncoder(format).Encode(e, true)
+		for _, dec := range []Decoder{NewDecoder(format), NewDecoder("")} {
+			r, err := dec.Decode(encoded)
+			if err != nil {
+				t.Fatalf("%s: %v", format, err)
+			}
+			if !r.Time.Equal(e.timestamp) {
+				t.Errorf("expected %v, got %v", e.timestamp, r.Time)
+			}
+			if reencoded := NewEncoder(format).Encode(r.Entry(), true); reencoded != encoded {
+				t.Errorf("expected %v, got %v", encoded, reencoded)
+			}
+		}
+	}
+}
+
+func TestDecodeUnstructured(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"plain text",
+		"2019/10/10 10:10:10 main INFO message",
+		`{"not": "an entry"}`,
+		`key=value other="unterminated`,
+	} {
+		if r, err := NewDecoder("").Decode(line); err == nil {
+			t.Errorf("expected an error for %q, got %+v", line, r)
+		}
+	}
+}
+
+func TestRecordUnregisteredLevel(t *testing.T) {
+	r := &Record{Level: "TEST-UNREGISTERED", Logger: "main", Message: "restored"}
+	e := r.Entry()
+	if level.IsValidName(r.Level) {
+		t.Errorf("expected log-level %s to remain unregistered", r.Level)
+	}
+	if e.Level() != r.Level {
+		t.Errorf("expected %v, got %v", r.Level, e.Level())
+	}
+	if encoded := NewEncoder("{{.Level}} {{.Message}}").Encode(e, true); encoded != "TEST-UNREGISTERED restored" {
+		t.Errorf("expected %q, got %q", "TEST-UNREGISTERED restored", encoded)
+	}
+}
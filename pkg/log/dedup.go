@@ -0,0 +1,150 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/octogo/log/internal/gid"
+)
+
+// deduplicator is implemented by outputs that can collapse repeated entries.
+type deduplicator interface {
+	SetDedup(time.Duration)
+}
+
+// deduper holds back consecutive entries of the same logger, log-level and
+// message. The held back entries are summarized when a different entry
+// arrives or the timeout passes. Its owner must serialize all calls.
+type deduper struct {
+	timeout time.Duration
+	last    Entry
+	count   int
+	since   time.Time
+	until   time.Time
+	timer   *time.Timer
+	gen     int
+	expired func(gen int)
+}
+
+func newDeduper(timeout time.Duration, expired func(gen int)) *deduper {
+	return &deduper{
+		timeout: timeout,
+		expired: expired,
+	}
+}
+
+// dedup returns the entries to write for the given entry.
+func (d *deduper) dedup(e Entry) []Entry {
+	now := time.Now()
+	if d.last != nil && isRepeated(d.last, e) {
+		if d.count == 0 {
+			d.gen++
+			gen := d.gen
+			d.timer = time.AfterFunc(d.timeout, func() { d.expired(gen) })
+		}
+		d.count++
+		d.until = now
+		return nil
+	}
+	out := d.summarize()
+	d.last = e
+	d.since = now
+	return append(out, e)
+}
+
+// expire returns the summary of held back entries, if the timer of the given
+// generation is still current.
+func (d *deduper) expire(gen int) []Entry {
+	if gen != d.gen {
+		return nil
+	}
+	return d.summarize()
+}
+
+// flush returns the summary of held back entries and stops the timer.
+func (d *deduper) flush() []Entry {
+	out := d.summarize()
+	d.last = nil
+	return out
+}
+
+func (d *deduper) summarize() []Entry {
+	if d.count == 0 {
+		return nil
+	}
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.gen++
+	summary := repeated(d.last, d.count, d.since, d.until)
+	d.count = 0
+	d.since = d.until
+	return []Entry{summary}
+}
+
+// isRepeated returns true if the given entries have the same logger,
+// log-level and message.
+func isRepeated(a, b Entry) bool {
+	return a.LevelLevel() == b.LevelLevel() &&
+		a.Logger() == b.Logger() &&
+		a.Message() == b.Message()
+}
+
+// repeatedEntry summarizes an entry that has been repeated.
+type repeatedEntry struct {
+	Entry
+	message  string
+	gid, lid uint64
+}
+
+func (e repeatedEntry) Message() string {
+	return e.message
+}
+
+func (e repeatedEntry) GID() string {
+	return fmt.Sprintf("%d", e.gid)
+}
+
+func (e repeatedEntry) LID() string {
+	return fmt.Sprintf("%d", e.lid)
+}
+
+func (e repeatedEntry) Stack() string {
+	return ""
+}
+
+// repeated returns an Entry that summarizes n repetitions of the given entry
+// between the given times. The summary is a new entry with IDs of its own.
+func repeated(e Entry, n int, since, until time.Time) Entry {
+	times := fmt.Sprintf("%d times", n)
+	if n == 1 {
+		times = "once"
+	}
+	msg := fmt.Sprintf(
+		"last message repeated %s over %s",
+		times, until.Sub(since).Round(time.Millisecond),
+	)
+	if es, ok := e.(*entryStruct); ok {
+		summary := *es
+		summary.gid = gid.Next()
+		summary.lid = nextLID(es.logger)
+		summary.timestamp = until
+		summary.message = msg
+		summary.stack = ""
+		summary.errs = nil
+		summary.errStack = ""
+		summary.fields = nil
+		return &summary
+	}
+	return repeatedEntry{e, msg, gid.Next(), nextLID(e.Logger())}
+}
+
+// nextLID returns the next logger ID of the Logger with the given name, or 0
+// if no such Logger is registered.
+func nextLID(name string) uint64 {
+	if logger := GetLogger(name); logger != nil {
+		return logger.uid.Next()
+	}
+	return 0
+}
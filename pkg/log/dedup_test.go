@@ -0,0 +1,51 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+func TestDedup(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-DEDUP")
+	defer os.Remove(f.Name())
+	output := GetOutput("file://" + f.Name()).(*FileOutput)
+	output.SetFormat("{{.Level}} {{.Message}}")
+	output.SetDedup(50 * time.Millisecond)
+	defer output.SetDedup(0)
+
+	for i := 0; i < 3; i++ {
+		logger.Warning("retrying")
+	}
+	logger.Info("connected")
+	logger.Info("connected")
+	time.Sleep(100 * time.Millisecond)
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := regexp.MustCompile("^WARNING retrying\n" +
+		"WARNING last message repeated 2 times over [0-9.]+m?s\n" +
+		"INFO connected\n" +
+		"INFO last message repeated once over [0-9.]+m?s\n$")
+	if !expected.Match(b) {
+		t.Errorf("unexpected output: %q", b)
+	}
+}
+
+func TestRepeatedIDs(t *testing.T) {
+	e := newEntry("retrying", testLogger, level.WARNING, "caller", "file", 42)
+	summary := repeated(e, 2, time.Now(), time.Now())
+	if summary.GID() == e.GID() || summary.LID() == e.LID() {
+		t.Errorf("expected new IDs, got %s/%s of %s/%s", summary.GID(), summary.LID(), e.GID(), e.LID())
+	}
+	wrapped := repeated(repeatedEntry{Entry: e}, 2, time.Now(), time.Now())
+	if wrapped.GID() == e.GID() {
+		t.Errorf("expected new GID, got %s", wrapped.GID())
+	}
+}
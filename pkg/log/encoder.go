@@ -0,0 +1,274 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// Built-in structured encodings that can be used as the format of an output.
+const (
+	JSONFormat   = "json"
+	LogfmtFormat = "logfmt"
+)
+
+// Encoder encodes entries into the lines written by an output.
+type Encoder interface {
+	Encode(e Entry, disableColors bool) string // encodes the given entry
+	UsesCaller() bool                          // true if caller information is rendered
+}
+
+// NewEncoder returns the Encoder for the given log-format.
+// The formats "json" and "logfmt" select the structured encodings, any other
+// format is treated as a template.
+func NewEncoder(format string) Encoder {
+	switch strings.ToLower(format) {
+	case JSONFormat:
+		return jsonEncoder{}
+	case LogfmtFormat:
+		return logfmtEncoder{}
+	default:
+		return newTemplateEncoder(format)
+	}
+}
+
+// ValidateFormat returns an error if the given log-format is neither a
+// structured encoding nor a valid template. Templates are executed against
+// a sample entry, so that undefined fields such as {{.Msg}} are reported.
+func ValidateFormat(format string) error {
+	if enc, ok := NewEncoder(format).(*templateEncoder); ok {
+		if enc.err != nil {
+			return enc.err
+		}
+		return enc.tmpl.Execute(ioutil.Discard, sampleEntry())
+	}
+	return nil
+}
+
+// sampleEntry returns an entry with every value set, which log-formats are
+// validated against.
+func sampleEntry() *entryStruct {
+	return &entryStruct{
+		timestamp: time.Now(),
+		level:     level.INFO,
+		message:   "sample message",
+		logger:    "sample",
+		gid:       1,
+		lid:       1,
+		caller:    "main.main",
+		file:      "main.go",
+		line:      1,
+		stack:     "main.main()\n\tmain.go:1",
+		errs:      []ErrorInfo{{Type: "*errors.errorString", Message: "sample error"}},
+		errStack:  "main.main\n\tmain.go:1",
+		fields:    map[string]interface{}{"key": "value"},
+	}
+}
+
+type templateEncoder struct {
+	tmpl       *template.Template
+	err        error
+	usesCaller bool
+	usesStack  bool
+	usesError  bool
+}
+
+func newTemplateEncoder(format string) *templateEncoder {
+	tmpl, err := template.New("octolog/entry").Parse(format)
+	return &templateEncoder{
+		tmpl:       tmpl,
+		err:        err,
+		usesCaller: formatUses(format, callerFields...),
+		usesStack:  formatUses(format, "Stack"),
+		usesError:  formatUses(format, errorFields...),
+	}
+}
+
+// Encode executes the template against the given entry. If the template does
+// not render the details of a logged error or the stack trace of the entry
+// itself, they are appended on the following lines. If the template fails,
+// the entry is written in a plain format along with the error.
+func (enc *templateEncoder) Encode(e Entry, disableColors bool) string {
+	if enc.err != nil {
+		return formatFailure(e, enc.err)
+	}
+	if es, ok := e.(*entryStruct); ok {
+		colored := *es
+		colored.disableColors = disableColors
+		e = &colored
+	}
+	buf := new(bytes.Buffer)
+	if err := enc.tmpl.Execute(buf, e); err != nil {
+		return formatFailure(e, err)
+	}
+	if details := errorDetails(e); details != "" && !enc.usesError {
+		buf.WriteString("\n" + details)
+	}
+	if stack := e.Stack(); stack != "" && !enc.usesStack {
+		buf.WriteString("\n" + stack)
+	}
+	return buf.String()
+}
+
+// formatFailure returns the given entry in a plain format along with the
+// error of its log-format.
+func formatFailure(e Entry, err error) string {
+	return fmt.Sprintf("%s %s %s %s %s (log-format error: %v)", e.Date(), e.Time(), e.Logger(), e.Level(), e.Message(), err)
+}
+
+func (enc *templateEncoder) UsesCaller() bool {
+	return enc.usesCaller
+}
+
+// structured holds the values of an Entry in the order they are encoded by
+// the structured encodings.
+type structured struct {
+	Time    string                 `json:"time"`
+	Level   string                 `json:"level"`
+	Logger  string                 `json:"logger"`
+	Message string                 `json:"message"`
+	GID     uint64                 `json:"gid"`
+	LID     uint64                 `json:"lid"`
+	PID     int                    `json:"pid"`
+	Func    string                 `json:"func,omitempty"`
+	File    string                 `json:"file,omitempty"`
+	Line    int                    `json:"line,omitempty"`
+	Stack   string                 `json:"stack,omitempty"`
+	Error   *structuredError       `json:"error,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// structuredError holds the details of a logged error.
+type structuredError struct {
+	Message string      `json:"message"`
+	Chain   []ErrorInfo `json:"chain"`
+	Stack   string      `json:"stack,omitempty"`
+}
+
+func newStructured(e Entry) structured {
+	s := structured{
+		Level:   e.Level(),
+		Logger:  e.Logger(),
+		Message: e.Message(),
+		Func:    e.Func(),
+		File:    e.File(),
+		Stack:   e.Stack(),
+		Fields:  e.Fields(),
+	}
+	if chain := e.ErrorChain(); len(chain) > 0 {
+		s.Error = &structuredError{
+			Message: e.Err(),
+			Chain:   chain,
+			Stack:   e.ErrorStack(),
+		}
+	}
+	if es, ok := e.(*entryStruct); ok {
+		s.Time = es.timestamp.Format(time.RFC3339Nano)
+	} else {
+		s.Time = e.Date() + " " + e.Time() + e.Nano()
+	}
+	s.GID, _ = strconv.ParseUint(e.GID(), 10, 64)
+	s.LID, _ = strconv.ParseUint(e.LID(), 10, 64)
+	s.PID, _ = strconv.Atoi(e.PID())
+	s.Line, _ = strconv.Atoi(e.Line())
+	return s
+}
+
+type jsonEncoder struct{}
+
+// Encode returns the given entry as a single line JSON object.
+// Fields that can not be marshaled are logged in their string
+// representation.
+func (jsonEncoder) Encode(e Entry, disableColors bool) string {
+	s := newStructured(e)
+	b, err := json.Marshal(s)
+	if err != nil && len(s.Fields) > 0 {
+		fields := make(map[string]interface{}, len(s.Fields))
+		for k, v := range s.Fields {
+			fields[k] = fmt.Sprintf("%v", v)
+		}
+		s.Fields = fields
+		b, err = json.Marshal(s)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func (jsonEncoder) UsesCaller() bool {
+	return true
+}
+
+type logfmtEncoder struct{}
+
+// Encode returns the given entry as a single line of key=value pairs.
+func (logfmtEncoder) Encode(e Entry, disableColors bool) string {
+	s := newStructured(e)
+	buf := new(bytes.Buffer)
+	writeLogfmt(buf, "time", s.Time)
+	writeLogfmt(buf, "level", s.Level)
+	writeLogfmt(buf, "logger", s.Logger)
+	writeLogfmt(buf, "message", s.Message)
+	writeLogfmt(buf, "gid", strconv.FormatUint(s.GID, 10))
+	writeLogfmt(buf, "lid", strconv.FormatUint(s.LID, 10))
+	writeLogfmt(buf, "pid", strconv.Itoa(s.PID))
+	if s.Func != "" {
+		writeLogfmt(buf, "func", s.Func)
+		writeLogfmt(buf, "file", s.File)
+		writeLogfmt(buf, "line", strconv.Itoa(s.Line))
+	}
+	if s.Stack != "" {
+		writeLogfmt(buf, "stack", s.Stack)
+	}
+	if s.Error != nil {
+		types := make([]string, len(s.Error.Chain))
+		for i := range s.Error.Chain {
+			types[i] = s.Error.Chain[i].Type
+		}
+		writeLogfmt(buf, "error", s.Error.Message)
+		writeLogfmt(buf, "error_types", strings.Join(types, ","))
+		if s.Error.Stack != "" {
+			writeLogfmt(buf, "error_stack", s.Error.Stack)
+		}
+	}
+	keys := make([]string, 0, len(s.Fields))
+	for k := range s.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i := range keys {
+		writeLogfmt(buf, keys[i], fmt.Sprintf("%v", s.Fields[keys[i]]))
+	}
+	return buf.String()
+}
+
+func (logfmtEncoder) UsesCaller() bool {
+	return true
+}
+
+// writeLogfmt writes the given key and value to the given buffer, quoting the
+// value if necessary.
+func writeLogfmt(buf *bytes.Buffer, key, value string) {
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	if value == "" || strings.ContainsAny(value, " =\"\\") || strings.IndexFunc(value, isControl) >= 0 {
+		value = strconv.Quote(value)
+	}
+	buf.WriteString(value)
+}
+
+func isControl(r rune) bool {
+	return r < ' ' || r == 0x7f
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(NewEncoder(JSONFormat).Encode(testEntry, true)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for key, expected := range map[string]interface{}{
+		"message": msg,
+		"level":   "INFO",
+		"logger":  "TEST-LOGGER",
+		"func":    "caller",
+		"line":    float64(42),
+	} {
+		if decoded[key] != expected {
+			t.Errorf("expected %v, got %v", expected, decoded[key])
+		}
+	}
+	if _, exists := decoded["stack"]; exists {
+		t.Errorf("expected %v, got %v", nil, decoded["stack"])
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	encoded := NewEncoder(LogfmtFormat).Encode(testEntry, true)
+	expected := `level=INFO logger=TEST-LOGGER message="TEST MESSAGE"`
+	if !strings.Contains(encoded, expected) {
+		t.Errorf("expected %v in %v", expected, encoded)
+	}
+}
+
+func TestStacktrace(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-STACK")
+	defer os.Remove(f.Name())
+	output := GetOutput("file://" + f.Name()).(*FileOutput)
+	output.SetFormat(JSONFormat)
+	output.SetStacktrace([]level.Level{level.ERROR})
+
+	logger.Info("no stack")
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(lastLine(t, f)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := decoded["stack"]; exists {
+		t.Errorf("expected %v, got %v", nil, decoded["stack"])
+	}
+
+	logger.Error("stack")
+	if err := json.Unmarshal([]byte(lastLine(t, f)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	stack, _ := decoded["stack"].(string)
+	if !strings.HasPrefix(stack, "github.com/octogo/log/pkg/log.TestStacktrace()") {
+		t.Errorf("expected stack of TestStacktrace, got %v", stack)
+	}
+	if strings.Contains(stack, "runtime.") {
+		t.Errorf("expected no runtime frames, got %v", stack)
+	}
+}
+
+func TestTemplateEncoderEscaping(t *testing.T) {
+	message := `a "b" <c> & d's`
+	e := newEntry(message, testLogger, level.INFO, "caller", "file", 42)
+	if encoded := NewEncoder("{{.Message}}").Encode(e, true); encoded != message {
+		t.Errorf("expected %q, got %q", message, encoded)
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for format, valid := range map[string]bool{
+		JSONFormat:                      true,
+		"{{.Date}} {{.Message}}":        true,
+		`{{index .Fields "key"}}`:       true,
+		"{{.Message":                    false,
+		"{{.Msg}}":                      false,
+		"{{if .Err}}{{.Mesage}}{{end}}": false,
+	} {
+		if err := ValidateFormat(format); (err == nil) != valid {
+			t.Errorf("%q: expected valid %v, got %v", format, valid, err)
+		}
+	}
+}
+
+func TestTemplateEncoderFailure(t *testing.T) {
+	e := newEntry("hello", testLogger, level.INFO, "caller", "file", 42)
+	for _, format := range []string{"{{.Msg}}", "{{.Message"} {
+		encoded := NewEncoder(format).Encode(e, true)
+		if !strings.Contains(encoded, "INFO hello (log-format error: ") {
+			t.Errorf("%q: expected the entry along with the error, got %q", format, encoded)
+		}
+	}
+}
@@ -1,9 +1,7 @@
 package log
 
 import (
-	"bytes"
 	"fmt"
-	"html/template"
 	"os"
 	"time"
 
@@ -31,6 +29,11 @@ type Entry interface {
 	Func() string
 	File() string
 	Line() string
+	Stack() string
+	Err() string
+	ErrorChain() []ErrorInfo
+	ErrorStack() string
+	Fields() map[string]interface{}
 	Formatted(f string, disableColors bool) string
 	LevelLevel() level.Level
 }
@@ -44,7 +47,17 @@ type entryStruct struct {
 	caller        string
 	file          string
 	line          int
+	stack         string
+	errs          []ErrorInfo
+	errStack      string
+	fields        map[string]interface{}
 	disableColors bool
+
+	// restored entries have been logged by another process (see Record),
+	// possibly with a log-level that is not registered in this one
+	restored  bool
+	pid, ppid int
+	levelName string
 }
 
 func newEntry(
@@ -53,7 +66,7 @@ func newEntry(
 	lvl level.Level,
 	caller, file string,
 	line int,
-) Entry {
+) *entryStruct {
 	return &entryStruct{
 		timestamp: time.Now(),
 		level:     lvl,
@@ -75,15 +88,27 @@ func (e entryStruct) Color() string {
 	if e.disableColors {
 		return ""
 	}
-	return e.LevelLevel().Color().String()
+	return e.colorSequence().String()
 }
 
 func (e entryStruct) BoldColor() string {
 	if e.disableColors {
 		return ""
 	}
-	e.LevelLevel().Color().SetAttribute(color.Bold)
-	return e.LevelLevel().Color().String()
+	seq := e.colorSequence()
+	seq.SetAttribute(color.Bold)
+	return seq.String()
+}
+
+// colorSequence returns the color of the log-level of this entry, which is
+// magenta for log-levels that are not registered or have no color.
+func (e entryStruct) colorSequence() color.Sequence {
+	if e.levelName == "" {
+		if seq := e.LevelLevel().Color(); seq != nil {
+			return seq
+		}
+	}
+	return color.New(color.NormalDisplay, color.Magenta)
 }
 
 func (e entryStruct) NoColor() string {
@@ -110,10 +135,16 @@ func (e entryStruct) Nano() string {
 }
 
 func (e entryStruct) PID() string {
+	if e.restored {
+		return fmt.Sprintf("%d", e.pid)
+	}
 	return fmt.Sprintf("%d", os.Getpid())
 }
 
 func (e entryStruct) PPID() string {
+	if e.restored {
+		return fmt.Sprintf("%d", e.ppid)
+	}
 	return fmt.Sprintf("%d", os.Getppid())
 }
 
@@ -130,6 +161,9 @@ func (e entryStruct) Logger() string {
 }
 
 func (e entryStruct) Level() string {
+	if e.levelName != "" {
+		return e.levelName
+	}
 	return e.level.String()
 }
 
@@ -149,16 +183,29 @@ func (e entryStruct) Line() string {
 	return fmt.Sprintf("%d", e.line)
 }
 
-func (e entryStruct) Formatted(f string, disableColors bool) string {
-	e.disableColors = disableColors
-	tmpl, err := template.New("octolog/entry").Parse(f)
-	if err != nil {
-		panic(err)
-	}
-	buf := new(bytes.Buffer)
-	err = tmpl.Execute(buf, e)
-	if err != nil {
-		panic(err)
+func (e entryStruct) Stack() string {
+	return e.stack
+}
+
+func (e entryStruct) Err() string {
+	if len(e.errs) == 0 {
+		return ""
 	}
-	return buf.String()
+	return e.errs[0].Message
+}
+
+func (e entryStruct) ErrorChain() []ErrorInfo {
+	return e.errs
+}
+
+func (e entryStruct) ErrorStack() string {
+	return e.errStack
+}
+
+func (e entryStruct) Fields() map[string]interface{} {
+	return e.fields
+}
+
+func (e entryStruct) Formatted(f string, disableColors bool) string {
+	return newTemplateEncoder(f).Encode(&e, disableColors)
 }
@@ -1 +1,126 @@
 package log
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// ErrorInfo describes a single error of a wrapped error chain.
+type ErrorInfo struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// Fielder is implemented by errors that carry additional fields.
+// The fields of all errors in a wrapped chain are logged alongside the error.
+type Fielder interface {
+	Fields() map[string]interface{}
+}
+
+// errorFields lists the fields of an Entry holding error information.
+var errorFields = []string{"Err", "ErrorChain", "ErrorStack", "Fields"}
+
+// With returns a Logger that attaches the given error to all entries it logs.
+// The returned Logger shares its name, wants, outputs and counters with l.
+func (l *Logger) With(err error) *Logger {
+	derived := l.derive()
+	derived.err = err
+	return derived
+}
+
+// Err logs the given error with log-level ERROR, along with its wrapped
+// chain, the types of all errors in it and any fields they carry.
+func (l *Logger) Err(err error) {
+	if err == nil {
+		return
+	}
+	l.With(err).log(errorMessage(err), level.ERROR)
+}
+
+// errorMessage returns the message of the given error, redacted if it
+// satisfies Redactor.
+func errorMessage(err error) string {
+	if redacted, ok := err.(Redactor); ok {
+		return redacted.Redacted()
+	}
+	return err.Error()
+}
+
+// unwrap returns the error wrapped by the given error or nil.
+func unwrap(err error) error {
+	if wrapper, ok := err.(interface{ Unwrap() error }); ok {
+		return wrapper.Unwrap()
+	}
+	return nil
+}
+
+// setError records the given error in this entry.
+func (e *entryStruct) setError(err error) {
+	for ; err != nil; err = unwrap(err) {
+		e.errs = append(e.errs, ErrorInfo{
+			Message: errorMessage(err),
+			Type:    fmt.Sprintf("%T", err),
+		})
+		if fielder, ok := err.(Fielder); ok {
+			fields := fielder.Fields()
+			if e.fields == nil {
+				e.fields = make(map[string]interface{}, len(fields))
+			}
+			for k, v := range fields {
+				if _, exists := e.fields[k]; !exists {
+					e.fields[k] = v
+				}
+			}
+		}
+		if e.errStack == "" {
+			e.errStack = errorStack(err)
+		}
+	}
+}
+
+// errorStack returns the stack trace attached to the given error by libraries
+// like github.com/pkg/errors, which provide it through a StackTrace() method.
+func errorStack(err error) string {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%+v", method.Call(nil)[0].Interface()))
+}
+
+// errorDetails returns the error chain, fields and error stack of the given
+// entry as indented lines, or an empty string if the message of the entry
+// already tells everything.
+func errorDetails(e Entry) string {
+	var (
+		chain  = e.ErrorChain()
+		fields = e.Fields()
+		stack  = e.ErrorStack()
+	)
+	if len(chain) == 0 && len(fields) == 0 {
+		return ""
+	}
+	if len(chain) == 1 && len(fields) == 0 && stack == "" && chain[0].Message == e.Message() {
+		return ""
+	}
+	lines := make([]string, 0, len(chain)+len(fields)+1)
+	for i := range chain {
+		lines = append(lines, "\t"+chain[i].Type+": "+chain[i].Message)
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i := range keys {
+		lines = append(lines, fmt.Sprintf("\t%s=%v", keys[i], fields[keys[i]]))
+	}
+	if stack != "" {
+		lines = append(lines, stack)
+	}
+	return strings.Join(lines, "\n")
+}
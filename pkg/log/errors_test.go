@@ -0,0 +1,84 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+type wrappingError struct {
+	msg string
+	err error
+}
+
+func (e wrappingError) Error() string {
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e wrappingError) Unwrap() error {
+	return e.err
+}
+
+func (e wrappingError) Fields() map[string]interface{} {
+	return map[string]interface{}{"attempt": 3}
+}
+
+func TestLoggerErr(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-ERR")
+	defer os.Remove(f.Name())
+	GetOutput("file://" + f.Name()).SetFormat(JSONFormat)
+
+	err := wrappingError{"connecting", errors.New("refused")}
+	logger.Err(err)
+	var decoded structured
+	if err := json.Unmarshal([]byte(lastLine(t, f)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Message != err.Error() || decoded.Level != "ERROR" {
+		t.Errorf("expected %v, got %v", err.Error(), decoded.Message)
+	}
+	if decoded.Error == nil || len(decoded.Error.Chain) != 2 {
+		t.Fatalf("expected chain of %v errors, got %v", 2, decoded.Error)
+	}
+	if decoded.Error.Chain[0].Type != "log.wrappingError" || decoded.Error.Chain[1].Message != "refused" {
+		t.Errorf("unexpected chain: %v", decoded.Error.Chain)
+	}
+	if decoded.Fields["attempt"] != float64(3) {
+		t.Errorf("expected %v, got %v", 3, decoded.Fields["attempt"])
+	}
+
+	GetOutput("file://" + f.Name()).SetFormat("{{.Message}}")
+	logger.With(err).Warning("retrying")
+	b, _ := ioutil.ReadFile(f.Name())
+	if !strings.HasSuffix(string(b), "retrying\n\tlog.wrappingError: connecting: refused\n\t*errors.errorString: refused\n\tattempt=3\n") {
+		t.Errorf("unexpected output: %q", b)
+	}
+}
+
+func TestLoggerErrorSingleLine(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-ERROR-LINE")
+	defer os.Remove(f.Name())
+	GetOutput("file://" + f.Name()).SetFormat("{{.Level}} {{.Message}}")
+
+	err := wrappingError{"open config", errors.New("not found")}
+	logger.Error(err)
+	logger.Log(level.ERROR, err)
+	b, readErr := ioutil.ReadFile(f.Name())
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	expected := "ERROR open config: not found\nERROR open config: not found\n"
+	if string(b) != expected {
+		t.Errorf("expected %q, got %q", expected, b)
+	}
+
+	logger.Err(err)
+	if line := lastLine(t, f); line != "\tattempt=3" {
+		t.Errorf("expected the details of the error, got %q", line)
+	}
+}
@@ -0,0 +1,28 @@
+package log
+
+// WithFields returns a Logger that attaches the given fields to all entries
+// it logs, in addition to the fields of l. Values satisfying Redactor are
+// attached redacted. The returned Logger shares its name, wants, outputs and
+// counters with l.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	derived := l.derive()
+	derived.fields = make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		derived.fields[k] = v
+	}
+	for k, v := range fields {
+		if redacted, ok := v.(Redactor); ok {
+			v = redacted.Redacted()
+		}
+		derived.fields[k] = v
+	}
+	return derived
+}
+
+// setFields records the given fields in this entry.
+func (e *entryStruct) setFields(fields map[string]interface{}) {
+	e.fields = make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+}
@@ -0,0 +1,37 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestLoggerWithFields(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-FIELDS")
+	defer os.Remove(f.Name())
+	GetOutput("file://" + f.Name()).SetFormat(JSONFormat)
+
+	derived := logger.WithFields(map[string]interface{}{"attempt": 1, "host": "a"})
+	derived.WithFields(map[string]interface{}{"host": "b"}).
+		Err(wrappingError{"connecting", errors.New("refused")})
+	var decoded structured
+	if err := json.Unmarshal([]byte(lastLine(t, f)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Fields["host"] != "b" || decoded.Fields["attempt"] != float64(1) {
+		t.Errorf("unexpected fields: %v", decoded.Fields)
+	}
+	if decoded.Logger != "TEST-FIELDS" || decoded.Error == nil {
+		t.Errorf("unexpected entry: %v", decoded)
+	}
+
+	logger.Info("plain")
+	decoded = structured{}
+	if err := json.Unmarshal([]byte(lastLine(t, f)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Fields != nil {
+		t.Errorf("expected no fields, got %v", decoded.Fields)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/octogo/log/internal/lib"
 	"github.com/octogo/log/pkg/color"
@@ -30,18 +31,28 @@ func Init() {
 		DefaultLogFormat,
 	)
 	for i := range DefaultOutputs {
-		loadOutput(DefaultOutputs[i])
+		mustLoadOutput(DefaultOutputs[i])
 	}
 	defaultLogger = NewLogger(LoggerName, nil)
 }
 
 // Configure configures this package according to the given configuration.
+// It panics if the configuration is invalid, see ConfigureE.
 func Configure(c *config.Config) {
+	if err := ConfigureE(c); err != nil {
+		panic(err)
+	}
+}
+
+// configure applies the given validated configuration.
+func configure(c *config.Config) {
+	configMu.Lock()
 	DefaultLogFormat = c.DefaultFormat
 	LoggerName = c.LoggerName
 	if c.DefaultOutputs != nil && len(c.DefaultOutputs) > 0 {
 		DefaultOutputs = c.DefaultOutputs
 	}
+	configMu.Unlock()
 	// call Init() after configuring defaults
 	loadLevels(c.Levels)
 	loadOutputs(c.Outputs...)
@@ -54,69 +65,57 @@ func loadLevels(levels []config.Level) {
 		return
 	}
 	for i := range levels {
-		switch strings.ToUpper(levels[i].Color) {
-		case "BLACK":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Black))
-		case "RED":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Red))
-		case "GREEN":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Green))
-		case "YELLOW":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Yellow))
-		case "BLUE":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Blue))
-		case "MAGENTA":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Magenta))
-		case "CYAN":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.Cyan))
-		case "WHITE":
-			level.Register(levels[i].Name, color.New(color.NormalDisplay, color.White))
-		default:
-			level.Register(levels[i].Name, color.NewLiteral(levels[i].Color))
+		seq, err := color.Parse(levels[i].Color)
+		if err != nil {
+			seq = color.NewLiteral(levels[i].Color)
 		}
+		level.Register(levels[i].Name, seq)
 	}
 }
 
-func loadOutput(url string) Output {
+func loadOutput(url string) (Output, error) {
+	if output := GetOutput(url); output != nil {
+		return output, nil
+	}
 	schema, uri, err := lib.ParseURL(url)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	switch strings.ToLower(schema) {
 	case "file":
 		switch uri {
 		case os.Stdout.Name():
-			var output Output
-			if output = GetOutput("file://" + os.Stdout.Name()); output == nil {
-				return NewFileOutput(os.Stdout, nil, DefaultDebugFormat)
-			}
-			return output
+			return NewFileOutput(os.Stdout, nil, DefaultDebugFormat), nil
 		case os.Stderr.Name():
-			var output Output
-			if output = GetOutput("file://" + os.Stderr.Name()); output == nil {
-				return NewFileOutput(os.Stderr, nil, DefaultDebugFormat)
-			}
-			return output
+			return NewFileOutput(os.Stderr, nil, DefaultDebugFormat), nil
 		default:
-			f := lib.OpenFile(uri)
-			var output Output
-			if output = GetOutput(f.Name()); output == nil {
-				return NewFileOutput(f, nil, DefaultDebugFormat)
+			f, err := lib.OpenFile(uri)
+			if err != nil {
+				return nil, err
 			}
-			return output
+			return NewFileOutput(f, nil, DefaultDebugFormat), nil
 		}
 	default:
-		panic(errors.New("unsupported schema in URL: " + schema))
+		return nil, errors.New("unsupported schema in URL: " + schema)
 	}
 }
 
+// mustLoadOutput wraps loadOutput and panics on errors.
+func mustLoadOutput(url string) Output {
+	output, err := loadOutput(url)
+	if err != nil {
+		panic(err)
+	}
+	return output
+}
+
 func loadOutputs(configuredOutputs ...config.Output) []Output {
 	if configuredOutputs == nil || len(configuredOutputs) == 0 {
 		return []Output{}
 	}
 	outputs := make([]Output, len(configuredOutputs))
 	for i := range configuredOutputs {
-		o := loadOutput(configuredOutputs[i].URL)
+		o := mustLoadOutput(configuredOutputs[i].URL)
 		o.SetWants(lib.ParseLevels(configuredOutputs[i].Wants...))
 
 		var format string
@@ -126,6 +125,14 @@ func loadOutputs(configuredOutputs ...config.Output) []Output {
 			format = configuredOutputs[i].Format
 		}
 		o.SetFormat(format)
+		if configuredOutputs[i].Stacktrace != "" {
+			if st, ok := o.(stackTracer); ok {
+				st.SetStacktrace(lib.ParseThreshold(configuredOutputs[i].Stacktrace))
+			}
+		}
+		if dd, ok := o.(deduplicator); ok {
+			dd.SetDedup(loadDuration(configuredOutputs[i].Dedup))
+		}
 		outputs[i] = o
 	}
 	return outputs
@@ -142,7 +149,51 @@ func loadLoggers(configuredLoggers ...config.Logger) []*Logger {
 			lib.ParseLevels(configuredLoggers[i].Wants...),
 			configuredLoggers[i].Outputs...,
 		)
+		logger.SetSampling(loadSampling(configuredLoggers[i].Sampling))
+		logger.SetDedup(loadDuration(configuredLoggers[i].Dedup))
 		loggers[i] = logger
 	}
 	return loggers
 }
+
+func loadSampling(configured config.Sampling) *Sampling {
+	sampling, err := parseSampling(configured)
+	if err != nil {
+		panic(err)
+	}
+	return sampling
+}
+
+func parseSampling(configured config.Sampling) (*Sampling, error) {
+	if configured.First <= 0 {
+		return nil, nil
+	}
+	interval, err := parseDuration(configured.Interval)
+	if err != nil {
+		return nil, err
+	}
+	if interval == 0 {
+		interval = time.Second
+	}
+	return &Sampling{
+		Interval:   interval,
+		First:      configured.First,
+		Thereafter: configured.Thereafter,
+		ByCaller:   strings.ToLower(configured.By) == "caller",
+	}, nil
+}
+
+func loadDuration(configured string) time.Duration {
+	d, err := parseDuration(configured)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func parseDuration(configured string) (time.Duration, error) {
+	if configured == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(configured)
+}
@@ -2,9 +2,10 @@ package log
 
 import (
 	"fmt"
-	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/octogo/log/pkg/level"
 	"github.com/octogo/log/pkg/uid"
@@ -13,13 +14,23 @@ import (
 var defaultLogger *Logger
 
 // Logger is the primary interface for using octolog in other packages.
+// Outputs lists the URLs of the outputs of a Logger; use AddOutput,
+// RemoveOutput or SetOutputs to change them.
 type Logger struct {
 	Name    string
 	wants   []level.Level
 	Outputs []string
 	uid     *uid.UID
 	outputs []Output
+	outGen  uint64
 	mu      *sync.Mutex
+	helpers map[string]bool
+	sampler *sampler
+	dedup   *deduper
+	base    *Logger
+	skip    int
+	err     error
+	fields  map[string]interface{}
 }
 
 // NewLogger returns an initialized Logger.
@@ -28,14 +39,20 @@ func NewLogger(name string, wants []level.Level, Outputs ...string) *Logger {
 		name = LoggerName
 	}
 	if Outputs == nil || len(Outputs) == 0 {
-		Outputs = DefaultOutputs
+		_, Outputs = defaults()
 	}
+	return newLogger(name, wants, Outputs)
+}
+
+// newLogger returns an initialized Logger with the given outputs.
+func newLogger(name string, wants []level.Level, Outputs []string) *Logger {
 	l := &Logger{
 		Name:    name,
 		wants:   wants,
 		Outputs: Outputs,
 		uid:     &uid.UID{},
 		mu:      &sync.Mutex{},
+		helpers: map[string]bool{},
 	}
 	return RegisterLogger(name, l)
 }
@@ -47,53 +64,263 @@ func (l *Logger) NewLogger(name string) *Logger {
 		return l
 	}
 	name = strings.Join([]string{l.Name, name}, ".")
-	newLogger := NewLogger(name, l.wants)
-	newLogger.Outputs = l.Outputs
+	o := l.origin()
+	o.mu.Lock()
+	wants, urls := o.wants, o.Outputs
+	o.mu.Unlock()
+	newLogger := NewLogger(name, wants)
+	newLogger.mu.Lock()
+	newLogger.Outputs = urls
+	newLogger.outputs = nil
+	newLogger.mu.Unlock()
 	return newLogger
 }
 
+// AddOutput resolves the output with the given URL and adds it to this
+// Logger, effective with the next entry.
+func (l *Logger) AddOutput(url string) error {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := range o.Outputs {
+		if o.Outputs[i] == url {
+			return nil
+		}
+	}
+	urls := make([]string, len(o.Outputs), len(o.Outputs)+1)
+	copy(urls, o.Outputs)
+	return o.setOutputs(append(urls, url))
+}
+
+// RemoveOutput removes the output with the given URL from this Logger and
+// returns true if this Logger had been logging to it.
+func (l *Logger) RemoveOutput(url string) bool {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := len(o.Outputs)
+	o.removeURL(url)
+	return len(o.Outputs) < n
+}
+
+// OutputURLs returns the URLs of the outputs of this Logger.
+func (l *Logger) OutputURLs() []string {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string{}, o.Outputs...)
+}
+
+// SetOutputs resolves the outputs with the given URLs and replaces all
+// outputs of this Logger with them (no URLs implies 'none'). If any URL
+// fails to resolve, the outputs of this Logger remain unchanged.
+func (l *Logger) SetOutputs(urls ...string) error {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.setOutputs(append([]string{}, urls...))
+}
+
+// setOutputs resolves the given URLs and replaces the outputs of this Logger
+// with them. The caller must hold l.mu.
+func (l *Logger) setOutputs(urls []string) error {
+	gen := atomic.LoadUint64(&outGen)
+	outputs := make([]Output, len(urls))
+	for i := range urls {
+		var err error
+		if outputs[i], err = loadOutput(urls[i]); err != nil {
+			return err
+		}
+	}
+	l.Outputs = urls
+	l.outputs = outputs
+	l.outGen = gen
+	return nil
+}
+
 func (l *Logger) log(msg string, lvl level.Level) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	configMu.RLock()
+	defer configMu.RUnlock()
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.wantsLevel(lvl) {
+		return
+	}
+	o.resolveOutputs()
+
 	var (
 		caller string
 		file   string
 		line   int
-		fpcs   = make([]uintptr, 1)
-		n      = runtime.Callers(3, fpcs)
 	)
-	if n != 0 {
-		f := runtime.FuncForPC(fpcs[0] - 1)
-		if f != nil {
-			file, line = f.FileLine(fpcs[0] - 1)
-			caller = f.Name()
+	if usesCaller(o.outputs) || o.sampler != nil && o.sampler.ByCaller {
+		caller, file, line = l.caller()
+	}
+	if o.sampler != nil {
+		key := msg
+		if o.sampler.ByCaller {
+			key = fmt.Sprintf("%s:%d", file, line)
+		}
+		if !o.sampler.sample(lvl, key) {
+			return
 		}
 	}
 
-	if l.outputs == nil {
-		l.outputs = make([]Output, len(l.Outputs))
-		for i := range l.Outputs {
-			l.outputs[i] = loadOutput(l.Outputs[i])
+	entry := newEntry(msg, o, lvl, caller, file, line)
+	if l.fields != nil {
+		entry.setFields(l.fields)
+	}
+	if l.err != nil {
+		entry.setError(l.err)
+	}
+	if usesStack(o.outputs, lvl) {
+		entry.stack = l.stack()
+	}
+	if o.dedup == nil {
+		o.write(entry)
+		return
+	}
+	for _, e := range o.dedup.dedup(entry) {
+		o.write(e)
+	}
+}
+
+// SetDedup configures this Logger to collapse consecutive entries of the same
+// log-level and message into a summary that is logged when a different entry
+// arrives or the given timeout passes (0 disables it).
+func (l *Logger) SetDedup(timeout time.Duration) {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.dedup != nil {
+		for _, e := range o.dedup.flush() {
+			o.write(e)
 		}
+		o.dedup = nil
+	}
+	if timeout > 0 {
+		o.dedup = newDeduper(timeout, o.expireDedup)
 	}
+}
 
-	entry := newEntry(msg, l, lvl, caller, file, line)
+func (l *Logger) expireDedup(gen int) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dedup == nil {
+		return
+	}
+	for _, e := range l.dedup.expire(gen) {
+		l.write(e)
+	}
+}
+
+// resolveOutputs resolves the URLs in l.Outputs to l.outputs, unless they
+// have been resolved since the registered outputs last changed. URLs that
+// fail to resolve, e.g. of custom outputs unregistered by Reset, are removed
+// from this Logger and the first error is returned.
+// The caller must hold l.mu.
+func (l *Logger) resolveOutputs() error {
+	gen := atomic.LoadUint64(&outGen)
+	if l.outputs != nil && l.outGen == gen {
+		return nil
+	}
+	var (
+		failed   []string
+		firstErr error
+	)
+	l.outputs = make([]Output, len(l.Outputs))
+	for i := range l.Outputs {
+		output, err := loadOutput(l.Outputs[i])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed = append(failed, l.Outputs[i])
+			continue
+		}
+		l.outputs[i] = output
+	}
+	for i := range failed {
+		l.removeURL(failed[i])
+	}
+	l.outGen = gen
+	return firstErr
+}
+
+// write passes the given entry to all outputs of this Logger.
+// Outputs that have been closed since they were resolved are resolved again,
+// other outputs failing to log the entry are removed from this Logger.
+// The caller must hold l.mu.
+func (l *Logger) write(entry Entry) {
+	l.resolveOutputs()
+	var failed []string
 	for i := range l.outputs {
 		_, err := l.outputs[i].Log(entry)
+		if err == errOutputClosed {
+			var output Output
+			if output, err = loadOutput(l.Outputs[i]); err == nil {
+				l.outputs[i] = output
+				_, err = output.Log(entry)
+			}
+		}
 		if err != nil {
-			l.Outputs = append(l.Outputs[:i], l.Outputs[i+1:]...)
-			l.outputs = append(l.outputs[:i], l.outputs[i+1:]...)
+			failed = append(failed, l.Outputs[i])
 		}
 	}
+	for i := range failed {
+		l.removeURL(failed[i])
+	}
+}
+
+// removeOutputURL removes the given output URL from this Logger.
+func (l *Logger) removeOutputURL(url string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeURL(url)
+}
+
+// removeURL removes the given output URL from this Logger.
+// The caller must hold l.mu.
+func (l *Logger) removeURL(url string) {
+	urls := make([]string, 0, len(l.Outputs))
+	outputs := make([]Output, 0, len(l.Outputs))
+	for i := range l.Outputs {
+		if l.Outputs[i] == url {
+			continue
+		}
+		urls = append(urls, l.Outputs[i])
+		if i < len(l.outputs) {
+			outputs = append(outputs, l.outputs[i])
+		}
+	}
+	l.Outputs = urls
+	if l.outputs != nil {
+		l.outputs = outputs
+	}
 }
 
 // SetWants configures this logger to only accept entries of the given log-level.
 func (l *Logger) SetWants(wants []level.Level) {
-	l.wants = wants
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.wants = wants
 }
 
 // Wants returns true if this logger is configured to log the given log-level.
 func (l *Logger) Wants(lvl level.Level) bool {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.wantsLevel(lvl)
+}
+
+// wantsLevel returns true if this logger is configured to log the given
+// log-level. The caller must hold l.mu.
+func (l *Logger) wantsLevel(lvl level.Level) bool {
 	if l.wants == nil {
 		return true
 	}
@@ -105,6 +332,18 @@ func (l *Logger) Wants(lvl level.Level) bool {
 	return false
 }
 
+// WantedLevels returns the log-levels this logger is configured to log (nil
+// implies 'all').
+func (l *Logger) WantedLevels() []level.Level {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.wants == nil {
+		return nil
+	}
+	return append([]level.Level{}, o.wants...)
+}
+
 // Log logs the given value with the given log-level.
 func (l *Logger) Log(lvl level.Level, v interface{}) {
 	if redacted, ok := v.(Redactor); ok {
@@ -168,6 +407,8 @@ func (l *Logger) Warningf(format string, args ...interface{}) {
 }
 
 // Error logs the given string with log-level ERROR.
+// Errors are logged by their message only; use Err or With to log them
+// along with their wrapped chain.
 func (l *Logger) Error(v interface{}) {
 	if redacted, ok := v.(Redactor); ok {
 		v = redacted.Redacted()
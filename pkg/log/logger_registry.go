@@ -1,6 +1,11 @@
 package log
 
-import "sync"
+import (
+	"sort"
+	"sync"
+
+	"github.com/octogo/log/pkg/level"
+)
 
 var (
 	regLoggers = map[string]*Logger{}
@@ -21,3 +26,69 @@ func RegisterLogger(name string, logger *Logger) *Logger {
 	regLoggers[name] = logger
 	return regLoggers[name]
 }
+
+// GetLogger returns the Logger registered under the given name or nil if no
+// Logger with that name has been registered.
+func GetLogger(name string) *Logger {
+	logMu.Lock()
+	defer logMu.Unlock()
+	return regLoggers[name]
+}
+
+// Loggers returns all registered Loggers ordered by their names.
+func Loggers() []*Logger {
+	logMu.Lock()
+	defer logMu.Unlock()
+	loggers := make([]*Logger, 0, len(regLoggers))
+	for name := range regLoggers {
+		loggers = append(loggers, regLoggers[name])
+	}
+	sort.Slice(loggers, func(i, j int) bool { return loggers[i].Name < loggers[j].Name })
+	return loggers
+}
+
+// UnregisterLogger removes the Logger with the given name from the registry
+// and returns true if it had been registered. The Logger itself remains
+// usable, but a new Logger will be initialized for its name.
+func UnregisterLogger(name string) bool {
+	logMu.Lock()
+	defer logMu.Unlock()
+	_, exists := regLoggers[name]
+	delete(regLoggers, name)
+	return exists
+}
+
+// Reset restores the initial state of this package: it stops the sampling
+// and deduplication of all Loggers, removes them and all Outputs from the
+// registries, closes the Outputs, unregisters custom log-levels and restores
+// the defaults of DefaultLogFormat, LoggerName and DefaultOutputs. Loggers
+// that are still in use re-resolve their outputs on their next entry, so
+// Init or Configure should be called after Reset.
+func Reset() {
+	RestoreVerbosity()
+	logMu.Lock()
+	loggers := regLoggers
+	regLoggers = map[string]*Logger{}
+	logMu.Unlock()
+	for _, logger := range loggers {
+		logger.SetSampling(nil)
+		logger.SetDedup(0)
+	}
+
+	outMu.Lock()
+	outputs := regOutputs
+	regOutputs = map[string]Output{}
+	outMu.Unlock()
+	outputsChanged()
+	for _, output := range outputs {
+		closeOutput(output)
+	}
+
+	defaultLogger = nil
+	configMu.Lock()
+	DefaultLogFormat = initialLogFormat
+	LoggerName = initialLoggerName
+	DefaultOutputs = append([]string{}, initialOutputs...)
+	configMu.Unlock()
+	level.Reset()
+}
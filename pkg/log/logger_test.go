@@ -0,0 +1,61 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+func TestLoggerWants(t *testing.T) {
+	output := &memoryOutput{url: "memory://wants"}
+	RegisterOutput(output.URL(), output)
+	defer UnregisterOutput(output.URL())
+	logger := NewLogger("TEST-WANTS", []level.Level{level.WARNING, level.ERROR}, output.URL())
+	defer UnregisterLogger(logger.Name)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warning("warning")
+	logger.Error("error")
+	if got := strings.Join(output.Messages(), ","); got != "warning,error" {
+		t.Errorf("expected %v, got %v", "warning,error", got)
+	}
+
+	logger.SetWants(nil)
+	logger.Info("info")
+	if got := strings.Join(output.Messages(), ","); got != "warning,error,info" {
+		t.Errorf("expected %v, got %v", "warning,error,info", got)
+	}
+}
+
+func TestDerivedLoggersConcurrently(t *testing.T) {
+	kept := &memoryOutput{url: "memory://derived-kept"}
+	RegisterOutput(kept.URL(), kept)
+	defer UnregisterOutput(kept.URL())
+	logger := NewLogger("TEST-DERIVED", nil, kept.URL())
+	defer UnregisterLogger(logger.Name)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			removed := &memoryOutput{url: "memory://derived-removed"}
+			RegisterOutput(removed.URL(), removed)
+			logger.AddOutput(removed.URL())
+			UnregisterOutput(removed.URL())
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		logger.WithCallerSkip(1).Info("skipped")
+		logger.With(errors.New("failed")).Info("with error")
+		logger.WithFields(map[string]interface{}{"i": i}).Info("with fields")
+	}
+	wg.Wait()
+	if got := len(kept.Messages()); got != 3000 {
+		t.Errorf("expected %v entries, got %v", 3000, got)
+	}
+}
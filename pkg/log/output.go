@@ -1,6 +1,13 @@
 package log
 
-import "github.com/octogo/log/pkg/level"
+import (
+	"errors"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// errOutputClosed is returned by outputs that log after being closed.
+var errOutputClosed = errors.New("output closed")
 
 // Output is defined as
 type Output interface {
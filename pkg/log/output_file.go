@@ -3,6 +3,7 @@ package log
 import (
 	"os"
 	"sync"
+	"time"
 
 	"github.com/octogo/log/internal/lib"
 	"github.com/octogo/log/pkg/level"
@@ -11,49 +12,124 @@ import (
 
 // FileOutput implements an output that writes the logs to a file.
 type FileOutput struct {
-	File   *os.File
-	wants  []level.Level
-	format string
-	mu     *sync.Mutex
+	File    *os.File
+	wants   []level.Level
+	stacks  []level.Level
+	format  string
+	encoder Encoder
+	dedup   *deduper
+	closed  bool
+	mu      *sync.Mutex
 }
 
 // NewFileOutput returns an initialized FileOutput.
 func NewFileOutput(file *os.File, wants []level.Level, format string) Output {
 	if format == "" {
-		format = DefaultLogFormat
+		format, _ = defaults()
 	}
 	output := &FileOutput{
-		File:   file,
-		wants:  wants,
-		format: format,
-		mu:     &sync.Mutex{},
+		File:    file,
+		wants:   wants,
+		format:  format,
+		encoder: NewEncoder(format),
+		mu:      &sync.Mutex{},
 	}
 	return RegisterOutput(output.URL(), output)
 }
 
 // Type returns the type of this output (i.e. file).
-func (fOut FileOutput) Type() string {
+func (fOut *FileOutput) Type() string {
 	return "file"
 }
 
 // URI returns the name of the underlying file.
-func (fOut FileOutput) URI() string {
+func (fOut *FileOutput) URI() string {
 	return fOut.File.Name()
 }
 
 // URL returns the URL of this output.
-func (fOut FileOutput) URL() string {
+func (fOut *FileOutput) URL() string {
 	return lib.URL(fOut.Type(), fOut.URI())
 }
 
 // Log writes the given Entry to the underlying file.
-func (fOut FileOutput) Log(e Entry) (n int, err error) {
+func (fOut *FileOutput) Log(e Entry) (n int, err error) {
 	fOut.mu.Lock()
 	defer fOut.mu.Unlock()
-	if fOut.Wants(e.LevelLevel()) {
-		return fOut.File.WriteString(e.Formatted(fOut.format, !terminal.IsTerminal(int(fOut.File.Fd()))) + "\n")
+	if fOut.closed {
+		return 0, errOutputClosed
+	}
+	if !fOut.wantsLevel(e.LevelLevel()) {
+		return 0, nil
+	}
+	if fOut.dedup == nil {
+		return fOut.write(e)
+	}
+	for _, e := range fOut.dedup.dedup(e) {
+		written, err := fOut.write(e)
+		if n += written; err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+func (fOut *FileOutput) write(e Entry) (int, error) {
+	if !fOut.usesStack(e.LevelLevel()) {
+		e = withoutStack(e)
+	}
+	return fOut.File.WriteString(fOut.encoder.Encode(e, !terminal.IsTerminal(int(fOut.File.Fd()))) + "\n")
+}
+
+// Close logs the summary of held back entries and closes the underlying file,
+// unless it is STDOUT or STDERR. Entries logged afterwards are rejected.
+func (fOut *FileOutput) Close() error {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	if fOut.closed {
+		return nil
+	}
+	if fOut.dedup != nil {
+		for _, e := range fOut.dedup.flush() {
+			fOut.write(e)
+		}
+		fOut.dedup = nil
+	}
+	fOut.closed = true
+	if fOut.File == os.Stdout || fOut.File == os.Stderr {
+		return nil
+	}
+	return fOut.File.Close()
+}
+
+// SetDedup configures this backend to collapse consecutive entries of the
+// same logger, log-level and message into a summary that is logged when a
+// different entry arrives or the given timeout passes (0 disables it).
+func (fOut *FileOutput) SetDedup(timeout time.Duration) {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	if fOut.dedup != nil {
+		for _, e := range fOut.dedup.flush() {
+			fOut.write(e)
+		}
+		fOut.dedup = nil
+	}
+	if timeout > 0 {
+		fOut.dedup = newDeduper(timeout, fOut.expireDedup)
+	}
+}
+
+func (fOut *FileOutput) expireDedup(gen int) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	if fOut.dedup == nil {
+		return
+	}
+	for _, e := range fOut.dedup.expire(gen) {
+		fOut.write(e)
 	}
-	return 0, nil
 }
 
 // SetFormat sets the format of this backend to the given string.
@@ -61,6 +137,41 @@ func (fOut *FileOutput) SetFormat(f string) {
 	fOut.mu.Lock()
 	defer fOut.mu.Unlock()
 	fOut.format = f
+	fOut.encoder = NewEncoder(f)
+}
+
+// UsesCaller returns true if the format of this output renders caller
+// information.
+func (fOut *FileOutput) UsesCaller() bool {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	return fOut.encoder.UsesCaller()
+}
+
+// SetStacktrace configures this backend to render stack traces for entries of
+// the given levels (nil implies 'none').
+func (fOut *FileOutput) SetStacktrace(levels []level.Level) {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	fOut.stacks = levels
+}
+
+// UsesStack returns true if this backend renders stack traces for the given
+// level.
+func (fOut *FileOutput) UsesStack(lvl level.Level) bool {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	return fOut.usesStack(lvl)
+}
+
+// usesStack is UsesStack for callers holding fOut.mu.
+func (fOut *FileOutput) usesStack(lvl level.Level) bool {
+	for i := range fOut.stacks {
+		if fOut.stacks[i] == lvl {
+			return true
+		}
+	}
+	return false
 }
 
 // SetWants configures this backend to only log entries of the given levels.
@@ -70,8 +181,34 @@ func (fOut *FileOutput) SetWants(wants []level.Level) {
 	fOut.wants = wants
 }
 
+// WantedLevels returns the log-levels this backend is configured to log (nil
+// implies 'all').
+func (fOut *FileOutput) WantedLevels() []level.Level {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	if fOut.wants == nil {
+		return nil
+	}
+	return append([]level.Level{}, fOut.wants...)
+}
+
+// Format returns the log-format of this backend.
+func (fOut *FileOutput) Format() string {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	return fOut.format
+}
+
 // Wants returns true if this backend is configured to log the given level.
-func (fOut FileOutput) Wants(lvl level.Level) bool {
+func (fOut *FileOutput) Wants(lvl level.Level) bool {
+	fOut.mu.Lock()
+	defer fOut.mu.Unlock()
+	return fOut.wantsLevel(lvl)
+}
+
+// wantsLevel returns true if this backend is configured to log the given
+// level. The caller must hold fOut.mu.
+func (fOut *FileOutput) wantsLevel(lvl level.Level) bool {
 	if fOut.wants == nil {
 		return true
 	}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// TestFileOutputConcurrentChanges is meant to be run with -race.
+func TestFileOutputConcurrentChanges(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-FILE-CONCURRENT")
+	defer os.Remove(f.Name())
+	output := GetOutput("file://" + f.Name()).(*FileOutput)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			logger.Info("concurrent")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			output.SetWants([]level.Level{level.INFO})
+			output.SetFormat("{{.Func}} {{.Message}}")
+			output.SetStacktrace(nil)
+			output.Wants(level.INFO)
+		}
+	}()
+	wg.Wait()
+	if output.Format() != "{{.Func}} {{.Message}}" {
+		t.Errorf("expected %q, got %q", "{{.Func}} {{.Message}}", output.Format())
+	}
+}
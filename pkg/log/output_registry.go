@@ -1,12 +1,18 @@
 package log
 
 import (
+	"io"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 var (
 	regOutputs = map[string]Output{}
 	outMu      = &sync.Mutex{}
+	// outGen is increased whenever registered outputs are replaced or
+	// removed, which makes Loggers re-resolve their outputs.
+	outGen uint64
 )
 
 // RegisterOutput registers the given output under the given name.
@@ -33,3 +39,64 @@ func GetOutput(url string) Output {
 	}
 	return nil
 }
+
+// Outputs returns all registered outputs ordered by their URLs.
+func Outputs() []Output {
+	outMu.Lock()
+	defer outMu.Unlock()
+	urls := make([]string, 0, len(regOutputs))
+	for url := range regOutputs {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	outputs := make([]Output, len(urls))
+	for i := range urls {
+		outputs[i] = regOutputs[urls[i]]
+	}
+	return outputs
+}
+
+// ReplaceOutput registers the given output under the given URL, replacing
+// the output registered before, which is closed and returned (or nil).
+// Loggers logging to the URL log to the new output from their next entry on.
+func ReplaceOutput(url string, output Output) Output {
+	outMu.Lock()
+	replaced := regOutputs[url]
+	regOutputs[url] = output
+	outMu.Unlock()
+	outputsChanged()
+	if replaced != nil && replaced != output {
+		closeOutput(replaced)
+	}
+	return replaced
+}
+
+// UnregisterOutput removes the output with the given URL from the registry
+// and from all registered Loggers and closes it. It returns true if the
+// output had been registered.
+func UnregisterOutput(url string) bool {
+	outMu.Lock()
+	output, exists := regOutputs[url]
+	delete(regOutputs, url)
+	outMu.Unlock()
+	for _, logger := range Loggers() {
+		logger.removeOutputURL(url)
+	}
+	outputsChanged()
+	if exists {
+		closeOutput(output)
+	}
+	return exists
+}
+
+// closeOutput closes the given output if it can be closed, e.g. FileOutput.
+func closeOutput(output Output) {
+	if c, ok := output.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// outputsChanged makes all Loggers re-resolve their outputs.
+func outputsChanged() {
+	atomic.AddUint64(&outGen, 1)
+}
@@ -0,0 +1,56 @@
+package log
+
+import (
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// Record holds the values of a logged entry, e.g. decoded from a log file.
+type Record struct {
+	Time       time.Time
+	Level      string
+	Logger     string
+	Message    string
+	GID        uint64
+	LID        uint64
+	PID        int
+	PPID       int
+	Func       string
+	File       string
+	Line       int
+	Stack      string
+	ErrorChain []ErrorInfo
+	ErrorStack string
+	Fields     map[string]interface{}
+}
+
+// Entry returns this Record as an Entry, so that it can be encoded by any
+// Encoder. Log-levels that are not registered are kept by name and rendered
+// in magenta, while the Entry reports them as INFO by LevelLevel.
+func (r *Record) Entry() Entry {
+	lvl, err := level.Parse(r.Level)
+	var levelName string
+	if err != nil {
+		lvl, levelName = level.INFO, r.Level
+	}
+	return &entryStruct{
+		timestamp: r.Time,
+		level:     lvl,
+		levelName: levelName,
+		message:   r.Message,
+		logger:    r.Logger,
+		gid:       r.GID,
+		lid:       r.LID,
+		caller:    r.Func,
+		file:      r.File,
+		line:      r.Line,
+		stack:     r.Stack,
+		errs:      r.ErrorChain,
+		errStack:  r.ErrorStack,
+		fields:    r.Fields,
+		restored:  true,
+		pid:       r.PID,
+		ppid:      r.PPID,
+	}
+}
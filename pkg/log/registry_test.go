@@ -0,0 +1,207 @@
+package log
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/octogo/log/pkg/color"
+	"github.com/octogo/log/pkg/level"
+)
+
+// memoryOutput records the messages of all entries it logs.
+type memoryOutput struct {
+	url      string
+	messages []string
+	mu       sync.Mutex
+}
+
+func (m *memoryOutput) Type() string           { return "memory" }
+func (m *memoryOutput) URI() string            { return m.url[len("memory://"):] }
+func (m *memoryOutput) URL() string            { return m.url }
+func (m *memoryOutput) SetFormat(string)       {}
+func (m *memoryOutput) SetWants([]level.Level) {}
+func (m *memoryOutput) Log(e Entry) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, e.Message())
+	return len(e.Message()), nil
+}
+
+func (m *memoryOutput) Messages() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.messages...)
+}
+
+func TestReplaceOutput(t *testing.T) {
+	first := &memoryOutput{url: "memory://replace"}
+	RegisterOutput(first.URL(), first)
+	logger := NewLogger("TEST-REPLACE", nil, first.URL())
+	logger.Info("first")
+
+	second := &memoryOutput{url: first.URL()}
+	if replaced := ReplaceOutput(first.URL(), second); replaced != first {
+		t.Errorf("expected %v, got %v", first, replaced)
+	}
+	logger.Info("second")
+	if got := first.Messages(); len(got) != 1 || got[0] != "first" {
+		t.Errorf("expected %v, got %v", []string{"first"}, got)
+	}
+	if got := second.Messages(); len(got) != 1 || got[0] != "second" {
+		t.Errorf("expected %v, got %v", []string{"second"}, got)
+	}
+
+	if !UnregisterOutput(first.URL()) {
+		t.Errorf("expected %v, got %v", true, false)
+	}
+	logger.Info("third")
+	if got := second.Messages(); len(got) != 1 {
+		t.Errorf("expected %v, got %v", 1, len(got))
+	}
+	if len(logger.Outputs) != 0 {
+		t.Errorf("expected %v, got %v", 0, logger.Outputs)
+	}
+}
+
+func TestUnregisterLogger(t *testing.T) {
+	logger := NewLogger("TEST-UNREGISTER", nil)
+	if GetLogger(logger.Name) != logger {
+		t.Errorf("expected %v, got %v", logger, GetLogger(logger.Name))
+	}
+	var found bool
+	for _, l := range Loggers() {
+		found = found || l == logger
+	}
+	if !found {
+		t.Errorf("expected %v in %v", logger, Loggers())
+	}
+	if !UnregisterLogger(logger.Name) {
+		t.Errorf("expected %v, got %v", true, false)
+	}
+	if GetLogger(logger.Name) != nil {
+		t.Errorf("expected %v, got %v", nil, GetLogger(logger.Name))
+	}
+	if NewLogger(logger.Name, nil) == logger {
+		t.Errorf("expected a new logger")
+	}
+}
+
+func TestLoggerOutputs(t *testing.T) {
+	first := &memoryOutput{url: "memory://outputs-1"}
+	second := &memoryOutput{url: "memory://outputs-2"}
+	RegisterOutput(first.URL(), first)
+	RegisterOutput(second.URL(), second)
+	logger := NewLogger("TEST-OUTPUTS", nil, first.URL())
+	logger.Info("first")
+
+	if err := logger.AddOutput(second.URL()); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("both")
+	if !logger.RemoveOutput(first.URL()) {
+		t.Errorf("expected %v, got %v", true, false)
+	}
+	logger.Info("second")
+	if got := first.Messages(); len(got) != 2 {
+		t.Errorf("expected %v, got %v", 2, got)
+	}
+	if got := second.Messages(); len(got) != 2 {
+		t.Errorf("expected %v, got %v", 2, got)
+	}
+
+	if err := logger.SetOutputs(first.URL(), "unsupported://output"); err == nil {
+		t.Errorf("expected error, got %v", err)
+	}
+	if len(logger.Outputs) != 1 || logger.Outputs[0] != second.URL() {
+		t.Errorf("expected %v, got %v", []string{second.URL()}, logger.Outputs)
+	}
+}
+
+func TestReset(t *testing.T) {
+	f, err := ioutil.TempFile("", "octolog-reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	NewFileOutput(f, nil, "{{.Message}}")
+	memory := &memoryOutput{url: "memory://reset"}
+	RegisterOutput(memory.URL(), memory)
+	logger := NewLogger("TEST-RESET", nil, "file://"+f.Name(), memory.URL())
+	logger.SetDedup(time.Minute)
+	logger.SetSampling(&Sampling{Interval: time.Minute, First: 1})
+	if _, _, err := level.Register("TEST-RESET", nil); err != nil {
+		t.Fatal(err)
+	}
+	LoggerName = "TEST-RESET"
+
+	Reset()
+	defer Init()
+	if len(Loggers()) != 0 || len(Outputs()) != 0 {
+		t.Errorf("expected no loggers and outputs, got %v and %v", Loggers(), Outputs())
+	}
+	if level.IsValidName("TEST-RESET") {
+		t.Errorf("expected log-level TEST-RESET to be unregistered")
+	}
+	if LoggerName != "main" || defaultLogger != nil {
+		t.Errorf("expected the defaults, got %v and %v", LoggerName, defaultLogger)
+	}
+	if _, err := f.WriteString("closed"); err == nil {
+		t.Errorf("expected the file output to be closed")
+	}
+	if logger.sampler != nil || logger.dedup != nil {
+		t.Errorf("expected sampling and deduplication to be stopped")
+	}
+
+	// the custom output can not be resolved anymore
+	logger.Info("after reset")
+	if got := logger.OutputURLs(); len(got) != 1 || got[0] != "file://"+f.Name() {
+		t.Errorf("expected %v, got %v", []string{"file://" + f.Name()}, got)
+	}
+	UnregisterOutput("file://" + f.Name())
+}
+
+func TestResetCustomLevel(t *testing.T) {
+	lvl, _, err := level.Register("TEST-RESET-LEVEL", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Reset()
+	defer Init()
+	f, err := ioutil.TempFile("", "octolog-reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	NewFileOutput(f, nil, "{{.Level}} {{.Message}}")
+	defer UnregisterOutput("file://" + f.Name())
+	logger := NewLogger("TEST-RESET-LEVEL", nil, "file://"+f.Name())
+	logger.Log(lvl, "after reset")
+	if expected, got := fmt.Sprintf("LEVEL(%d) after reset", lvl), lastLine(t, f); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	e := newEntry("after reset", logger, lvl, "caller", "file", 42)
+	if expected := color.New(color.NormalDisplay, color.Magenta).String(); e.Color() != expected {
+		t.Errorf("expected %q, got %q", expected, e.Color())
+	}
+}
+
+func TestReplaceFileOutput(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-REPLACE-FILE")
+	defer os.Remove(f.Name())
+	url := "file://" + f.Name()
+	replaced := GetOutput(url)
+	replacement := &memoryOutput{url: url}
+	ReplaceOutput(url, replacement)
+	defer UnregisterOutput(url)
+	if _, err := replaced.Log(testEntry); err != errOutputClosed {
+		t.Errorf("expected %v, got %v", errOutputClosed, err)
+	}
+	logger.Info("replaced")
+	if got := replacement.Messages(); len(got) != 1 {
+		t.Errorf("expected %v, got %v", 1, len(got))
+	}
+}
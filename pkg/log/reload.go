@@ -0,0 +1,252 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/octogo/log/internal/lib"
+	"github.com/octogo/log/pkg/config"
+)
+
+// WatchInterval defines how often Watch checks the configuration file for
+// changes.
+var WatchInterval = time.Second
+
+// Watch polls the configuration file at the given path and applies changes
+// to it with Reconfigure. Errors in the changed file are logged by the
+// standard logger, while logging continues with the configuration in effect.
+// The returned function stops watching the file.
+func Watch(path string) (stop func()) {
+	current, err := config.LoadE(path)
+	if err != nil {
+		current = &config.Config{}
+	}
+	modTime := fileModTime(path)
+	done := make(chan struct{})
+	once := &sync.Once{}
+	go func() {
+		ticker := time.NewTicker(WatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			mt := fileModTime(path)
+			if mt.IsZero() || mt.Equal(modTime) {
+				continue
+			}
+			modTime = mt
+			c, err := config.LoadE(path)
+			if err == nil {
+				err = Reconfigure(current, c)
+			}
+			if err != nil {
+				reportReloadError(path, err)
+				continue
+			}
+			current = c
+			if defaultLogger != nil {
+				defaultLogger.Noticef("reloaded configuration from %s", path)
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func reportReloadError(path string, err error) {
+	err = fmt.Errorf("failed to reload configuration from %s: %v", path, err)
+	if defaultLogger != nil {
+		defaultLogger.Err(err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
+
+// Reconfigure applies the differences between the configuration in effect
+// (old) and the given configuration to the registered loggers and outputs.
+// Formats, wants, stack traces, deduplication, sampling and outputs are
+// updated for every changed output and logger, outputs that have been
+// removed from the configuration are unregistered and closed and loggers
+// that have been removed are reset to the defaults. The whole configuration
+// is validated and its outputs are opened before anything is applied, so on
+// errors nothing changes. Logging is paused while the changes are applied,
+// so no entry is logged with a half-applied configuration.
+func Reconfigure(old, c *config.Config) error {
+	reconfigureMu.Lock()
+	defer reconfigureMu.Unlock()
+	if err := Validate(c); err != nil {
+		return err
+	}
+	if err := openOutputs(c); err != nil {
+		return err
+	}
+	defaultFormat, defaultOutputs := defaults()
+	if c.DefaultFormat != "" {
+		defaultFormat = c.DefaultFormat
+	}
+	if len(c.DefaultOutputs) > 0 {
+		defaultOutputs = c.DefaultOutputs
+	}
+
+	var (
+		oldOutputs = map[string]config.Output{}
+		oldLoggers = map[string]config.Logger{}
+		outputs    []*outputChange
+		loggers    []*loggerChange
+		removed    []string
+		reset      []string
+	)
+	for i := range old.Outputs {
+		oldOutputs[old.Outputs[i].URL] = old.Outputs[i]
+	}
+	for i := range old.Loggers {
+		oldLoggers[old.Loggers[i].Name] = old.Loggers[i]
+	}
+	formatChanged := defaultFormat != old.DefaultFormat
+	defaultOutputsChanged := !reflect.DeepEqual(old.DefaultOutputs, c.DefaultOutputs)
+	for i := range c.Outputs {
+		previous, exists := oldOutputs[c.Outputs[i].URL]
+		delete(oldOutputs, c.Outputs[i].URL)
+		if exists && reflect.DeepEqual(previous, c.Outputs[i]) &&
+			!(formatChanged && c.Outputs[i].Format == "") {
+			continue
+		}
+		outputs = append(outputs, prepareOutput(c.Outputs[i], defaultFormat))
+	}
+	for url := range oldOutputs {
+		removed = append(removed, url)
+	}
+	for i := range c.Loggers {
+		previous, exists := oldLoggers[c.Loggers[i].Name]
+		delete(oldLoggers, c.Loggers[i].Name)
+		if exists && reflect.DeepEqual(previous, c.Loggers[i]) &&
+			!(defaultOutputsChanged && len(c.Loggers[i].Outputs) == 0) {
+			continue
+		}
+		change := prepareLogger(c.Loggers[i], defaultOutputs)
+		change.path = fmt.Sprintf("loggers[%d].outputs", i)
+		loggers = append(loggers, change)
+	}
+	for name := range oldLoggers {
+		reset = append(reset, name)
+	}
+
+	// apply the changes at once
+	configMu.Lock()
+	defer configMu.Unlock()
+	var errs config.Errors
+	DefaultLogFormat = defaultFormat
+	DefaultOutputs = defaultOutputs
+	loadLevels(c.Levels)
+	for i := range outputs {
+		outputs[i].apply()
+	}
+	for i := range removed {
+		UnregisterOutput(removed[i])
+	}
+	for i := range loggers {
+		if err := loggers[i].apply(); err != nil {
+			errs.Add(loggers[i].path, err)
+		}
+	}
+	for i := range reset {
+		if logger := GetLogger(reset[i]); logger != nil {
+			logger.SetWants(nil)
+			if err := logger.SetOutputs(defaultOutputs...); err != nil {
+				errs.Add("defaultoutputs", err)
+			}
+			logger.SetSampling(nil)
+			logger.SetDedup(0)
+		}
+	}
+	return errs.Err()
+}
+
+// reconfigureMu serializes calls to Reconfigure.
+var reconfigureMu = &sync.Mutex{}
+
+// outputChange holds the validated configuration of an output.
+type outputChange struct {
+	output     Output
+	wants      []string
+	format     string
+	stacktrace string
+	dedup      time.Duration
+}
+
+func prepareOutput(c config.Output, defaultFormat string) *outputChange {
+	format := c.Format
+	if format == "" {
+		format = defaultFormat
+	}
+	return &outputChange{
+		output:     mustLoadOutput(c.URL),
+		wants:      c.Wants,
+		format:     format,
+		stacktrace: c.Stacktrace,
+		dedup:      loadDuration(c.Dedup),
+	}
+}
+
+func (change *outputChange) apply() {
+	change.output.SetWants(lib.ParseLevels(change.wants...))
+	change.output.SetFormat(change.format)
+	if st, ok := change.output.(stackTracer); ok {
+		if change.stacktrace == "" {
+			st.SetStacktrace(nil)
+		} else {
+			st.SetStacktrace(lib.ParseThreshold(change.stacktrace))
+		}
+	}
+	if dd, ok := change.output.(deduplicator); ok {
+		dd.SetDedup(change.dedup)
+	}
+}
+
+// loggerChange holds the validated configuration of a logger.
+type loggerChange struct {
+	path     string
+	name     string
+	wants    []string
+	urls     []string
+	sampling *Sampling
+	dedup    time.Duration
+}
+
+func prepareLogger(c config.Logger, defaultOutputs []string) *loggerChange {
+	urls := c.Outputs
+	if len(urls) == 0 {
+		urls = defaultOutputs
+	}
+	return &loggerChange{
+		name:     c.Name,
+		wants:    c.Wants,
+		urls:     urls,
+		sampling: loadSampling(c.Sampling),
+		dedup:    loadDuration(c.Dedup),
+	}
+}
+
+func (change *loggerChange) apply() error {
+	logger := RegisterLogger(change.name, newLogger(change.name, nil, change.urls))
+	logger.SetWants(lib.ParseLevels(change.wants...))
+	err := logger.SetOutputs(change.urls...)
+	logger.SetSampling(change.sampling)
+	logger.SetDedup(change.dedup)
+	return err
+}
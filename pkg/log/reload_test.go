@@ -0,0 +1,92 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/octogo/log/pkg/config"
+)
+
+func TestReconfigure(t *testing.T) {
+	output := &memoryOutput{url: "memory://reconfigure"}
+	RegisterOutput(output.URL(), output)
+	defer UnregisterOutput(output.URL())
+	old := &config.Config{
+		Loggers: []config.Logger{{Name: "TEST-RECONFIGURE", Wants: []string{"ERROR"}}},
+	}
+	logger := NewLogger("TEST-RECONFIGURE", nil)
+	logger.SetWants(nil)
+
+	invalid := &config.Config{
+		Loggers: []config.Logger{{
+			Name:    "TEST-RECONFIGURE",
+			Wants:   []string{"WARN"},
+			Outputs: []string{output.URL()},
+		}},
+	}
+	if err := Reconfigure(old, invalid); err == nil {
+		t.Errorf("expected an error, got %v", err)
+	}
+	if got := logger.OutputURLs(); len(got) == 1 && got[0] == output.URL() {
+		t.Errorf("expected no changes, got %v", got)
+	}
+
+	valid := &config.Config{
+		Loggers: []config.Logger{{
+			Name:    "TEST-RECONFIGURE",
+			Wants:   []string{"ERROR"},
+			Outputs: []string{output.URL()},
+		}},
+	}
+	if err := Reconfigure(old, valid); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("info")
+	logger.Error("error")
+	if got := output.Messages(); len(got) != 1 || got[0] != "error" {
+		t.Errorf("expected %v, got %v", []string{"error"}, got)
+	}
+}
+
+func TestReconfigureRemovedOutput(t *testing.T) {
+	f, err := ioutil.TempFile("", "octolog-reconfigure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	url := "file://" + f.Name()
+	old := &config.Config{
+		Outputs: []config.Output{{URL: url}},
+		Loggers: []config.Logger{{Name: "TEST-RECONFIGURE-REMOVED", Outputs: []string{url}}},
+	}
+	if err := Reconfigure(&config.Config{}, old); err != nil {
+		t.Fatal(err)
+	}
+	output := GetOutput(url)
+	logger := GetLogger("TEST-RECONFIGURE-REMOVED")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			logger.Info("concurrent")
+		}
+	}()
+	memory := &memoryOutput{url: "memory://reconfigure-removed"}
+	RegisterOutput(memory.URL(), memory)
+	defer UnregisterOutput(memory.URL())
+	if err := Reconfigure(old, &config.Config{
+		Loggers: []config.Logger{{Name: "TEST-RECONFIGURE-REMOVED", Outputs: []string{memory.URL()}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if GetOutput(url) != nil {
+		t.Errorf("expected %v to be unregistered", url)
+	}
+	if _, err := output.Log(testEntry); err != errOutputClosed {
+		t.Errorf("expected %v, got %v", errOutputClosed, err)
+	}
+}
@@ -0,0 +1,124 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// Sampling configures how many entries a Logger logs per interval.
+// Entries are grouped by log-level and either their message or their call
+// site. Of every group, the first First entries per Interval are logged and
+// after that every Thereafter-th entry (0 implies 'none').
+type Sampling struct {
+	Interval   time.Duration
+	First      int
+	Thereafter int
+	ByCaller   bool
+}
+
+// SetSampling configures this Logger to sample its entries (nil disables
+// sampling). The number of entries sampled away is logged periodically.
+func (l *Logger) SetSampling(s *Sampling) {
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.sampler != nil {
+		o.sampler.stop()
+		o.sampler = nil
+	}
+	if s == nil || s.First <= 0 || s.Interval <= 0 {
+		return
+	}
+	o.sampler = &sampler{
+		Sampling: *s,
+		counts:   map[sampleKey]int{},
+		dropped:  map[level.Level]int{},
+		mu:       &sync.Mutex{},
+		report:   o.reportSampled,
+	}
+}
+
+// reportSampled logs the number of entries that have been sampled away.
+func (l *Logger) reportSampled(dropped map[level.Level]int) {
+	levels := make([]level.Level, 0, len(dropped))
+	for lvl := range dropped {
+		levels = append(levels, lvl)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	configMu.RLock()
+	defer configMu.RUnlock()
+	o := l.origin()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := range levels {
+		o.write(newEntry(
+			fmt.Sprintf("sampled away %d entries", dropped[levels[i]]),
+			o, levels[i], "", "", 0,
+		))
+	}
+}
+
+type sampleKey struct {
+	level level.Level
+	key   string
+}
+
+type sampler struct {
+	Sampling
+	start   time.Time
+	counts  map[sampleKey]int
+	dropped map[level.Level]int
+	timer   *time.Timer
+	mu      *sync.Mutex
+	report  func(map[level.Level]int)
+}
+
+// sample returns true if the entry of the given log-level and key should be
+// logged.
+func (s *sampler) sample(lvl level.Level, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.start) >= s.Interval {
+		s.start = now
+		s.counts = map[sampleKey]int{}
+	}
+	k := sampleKey{lvl, key}
+	s.counts[k]++
+	n := s.counts[k]
+	if n <= s.First || s.Thereafter > 0 && (n-s.First)%s.Thereafter == 0 {
+		return true
+	}
+	s.dropped[lvl]++
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.Interval, s.flush)
+	}
+	return false
+}
+
+// flush reports and resets the number of entries sampled away.
+func (s *sampler) flush() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = map[level.Level]int{}
+	s.timer = nil
+	s.mu.Unlock()
+	if len(dropped) > 0 {
+		s.report(dropped)
+	}
+}
+
+// stop reports pending numbers of entries sampled away.
+func (s *sampler) stop() {
+	s.mu.Lock()
+	timer := s.timer
+	s.mu.Unlock()
+	if timer != nil && timer.Stop() {
+		go s.flush()
+	}
+}
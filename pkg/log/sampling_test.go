@@ -0,0 +1,35 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSampling(t *testing.T) {
+	logger, f := newCallerTestLogger(t, "TEST-SAMPLING")
+	defer os.Remove(f.Name())
+	GetOutput("file://" + f.Name()).SetFormat("{{.Message}}")
+	logger.SetSampling(&Sampling{
+		Interval:   50 * time.Millisecond,
+		First:      2,
+		Thereafter: 3,
+	})
+	defer logger.SetSampling(nil)
+
+	for i := 0; i < 10; i++ {
+		logger.Warning("flood")
+	}
+	logger.Warning("different")
+	time.Sleep(100 * time.Millisecond)
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "flood\nflood\nflood\nflood\ndifferent\nsampled away 6 entries\n"
+	if string(b) != expected {
+		t.Errorf("expected %q, got %q", expected, b)
+	}
+}
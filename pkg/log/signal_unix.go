@@ -0,0 +1,46 @@
+// +build linux aix darwin dragonfly freebsd netbsd openbsd
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// HandleSignals makes SIGUSR1 raise the verbosity of all loggers and outputs
+// (see RaiseVerbosity) and SIGUSR2 restore their configured levels (see
+// RestoreVerbosity). Each transition is logged with log-level NOTICE by the
+// standard logger. The returned function stops handling the signals.
+func HandleSignals() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				handleSignal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+func handleSignal(sig os.Signal) {
+	switch sig {
+	case syscall.SIGUSR1:
+		if RaiseVerbosity() && defaultLogger != nil {
+			defaultLogger.Noticef("raised verbosity to DEBUG on %s", sig)
+		}
+	case syscall.SIGUSR2:
+		if RestoreVerbosity() && defaultLogger != nil {
+			defaultLogger.Noticef("restored configured log-levels on %s", sig)
+		}
+	}
+}
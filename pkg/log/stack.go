@@ -0,0 +1,36 @@
+package log
+
+import "github.com/octogo/log/pkg/level"
+
+// stackUser is implemented by outputs that render stack traces for some
+// log-levels. Outputs not implementing it are never handed stack traces.
+type stackUser interface {
+	UsesStack(level.Level) bool
+}
+
+// stackTracer is implemented by outputs that can be configured to render
+// stack traces.
+type stackTracer interface {
+	SetStacktrace([]level.Level)
+}
+
+// usesStack returns true if any of the given outputs renders stack traces
+// for the given log-level.
+func usesStack(outputs []Output, lvl level.Level) bool {
+	for i := range outputs {
+		if user, ok := outputs[i].(stackUser); ok && user.UsesStack(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutStack returns a copy of the given Entry without its stack trace.
+func withoutStack(e Entry) Entry {
+	if es, ok := e.(*entryStruct); ok && es.stack != "" {
+		stripped := *es
+		stripped.stack = ""
+		return &stripped
+	}
+	return e
+}
@@ -0,0 +1,141 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/octogo/log/internal/lib"
+	"github.com/octogo/log/pkg/config"
+)
+
+// ConfigureE validates the given configuration and opens all of its outputs
+// before configuring this package according to it. On errors, nothing is
+// configured and the returned config.Errors describe all problems found.
+func ConfigureE(c *config.Config) error {
+	if err := Validate(c); err != nil {
+		return err
+	}
+	if err := openOutputs(c); err != nil {
+		return err
+	}
+	configure(c)
+	return nil
+}
+
+// Validate returns config.Errors describing all problems of the given
+// configuration, including unsupported output URLs and invalid formats.
+// Unlike ConfigureE, it does not open any outputs.
+func Validate(c *config.Config) error {
+	var errs config.Errors
+	if err := c.Validate(); err != nil {
+		errs = append(errs, err.(config.Errors)...)
+	}
+	if c.DefaultFormat != "" {
+		if err := ValidateFormat(c.DefaultFormat); err != nil {
+			errs.Add("defaultformat", err)
+		}
+	}
+	for i := range c.Outputs {
+		if c.Outputs[i].Format == "" {
+			continue
+		}
+		if err := ValidateFormat(c.Outputs[i].Format); err != nil {
+			errs.Add(fmt.Sprintf("outputs[%d].format", i), err)
+		}
+	}
+	for _, ref := range outputRefs(c) {
+		if err := validateURL(ref.url); err != nil {
+			errs.Add(ref.path, err)
+		}
+	}
+	return errs.Err()
+}
+
+// outputRef is a reference to an output in a configuration.
+type outputRef struct {
+	path string
+	url  string
+}
+
+// outputRefs returns all references to outputs in the given configuration,
+// including the default outputs.
+func outputRefs(c *config.Config) []outputRef {
+	var refs []outputRef
+	if len(c.DefaultOutputs) > 0 {
+		for i := range c.DefaultOutputs {
+			refs = append(refs, outputRef{fmt.Sprintf("defaultoutputs[%d]", i), c.DefaultOutputs[i]})
+		}
+	} else {
+		_, defaultOutputs := defaults()
+		for i := range defaultOutputs {
+			refs = append(refs, outputRef{"defaultoutputs", defaultOutputs[i]})
+		}
+	}
+	for i := range c.Outputs {
+		refs = append(refs, outputRef{fmt.Sprintf("outputs[%d].url", i), c.Outputs[i].URL})
+	}
+	for i := range c.Loggers {
+		for j := range c.Loggers[i].Outputs {
+			refs = append(refs, outputRef{fmt.Sprintf("loggers[%d].outputs[%d]", i, j), c.Loggers[i].Outputs[j]})
+		}
+	}
+	return refs
+}
+
+// validateURL returns an error if the given URL neither refers to a registered
+// output nor can be loaded.
+func validateURL(url string) error {
+	if url == "" || GetOutput(url) != nil {
+		return nil
+	}
+	schema, uri, err := lib.ParseURL(url)
+	if err != nil {
+		return fmt.Errorf("%v: %q", err, url)
+	}
+	switch strings.ToLower(schema) {
+	case "file":
+		if uri == "" {
+			return errors.New("missing path in URL")
+		}
+		return nil
+	default:
+		return errors.New("unsupported schema in URL: " + schema)
+	}
+}
+
+// openOutputs opens all files referenced by the given configuration that are
+// not yet registered as outputs. Only if all of them could be opened, they
+// are registered.
+func openOutputs(c *config.Config) error {
+	var (
+		errs   config.Errors
+		opened = map[string]*os.File{}
+	)
+	for _, ref := range outputRefs(c) {
+		if _, exists := opened[ref.url]; exists || GetOutput(ref.url) != nil {
+			continue
+		}
+		_, uri, _ := lib.ParseURL(ref.url)
+		if uri == os.Stdout.Name() || uri == os.Stderr.Name() {
+			continue
+		}
+		f, err := lib.OpenFile(uri)
+		if err != nil {
+			errs.Add(ref.path, err)
+			continue
+		}
+		opened[ref.url] = f
+	}
+	if len(errs) > 0 {
+		for _, f := range opened {
+			f.Close()
+		}
+		return errs
+	}
+	for _, f := range opened {
+		NewFileOutput(f, nil, DefaultDebugFormat)
+	}
+	return nil
+}
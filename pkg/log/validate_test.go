@@ -0,0 +1,82 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/octogo/log/pkg/config"
+)
+
+func TestConfigureE(t *testing.T) {
+	dir, err := ioutil.TempDir("", "octolog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	url := "file://" + filepath.Join(dir, "valid.log")
+
+	err = ConfigureE(&config.Config{
+		DefaultFormat: "{{.Message}",
+		Outputs: []config.Output{
+			{URL: url},
+			{URL: "syslog://localhost", Format: "json"},
+			{URL: url, Wants: []string{"INFO", "WARN"}},
+			{URL: "file://" + filepath.Join(dir, "missing", "invalid.log")},
+		},
+		Loggers: []config.Logger{{
+			Name:     "TEST-CONFIGURE-E",
+			Sampling: config.Sampling{By: "level"},
+		}},
+	})
+	errs, ok := err.(config.Errors)
+	if !ok {
+		t.Fatalf("expected config.Errors, got %#v", err)
+	}
+	expected := []string{
+		"outputs[2].url",
+		"outputs[2].wants[1]",
+		"loggers[0].sampling.by",
+		"defaultformat",
+		"outputs[1].url",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i := range expected {
+		if errs[i].Path != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], errs[i])
+		}
+	}
+	if GetOutput(url) != nil || GetLogger("TEST-CONFIGURE-E") != nil {
+		t.Errorf("expected nothing to be configured")
+	}
+
+	err = ConfigureE(&config.Config{
+		Outputs: []config.Output{{URL: url}, {URL: "file://" + filepath.Join(dir, "missing", "invalid.log")}},
+	})
+	if errs, ok := err.(config.Errors); !ok || len(errs) != 1 || errs[0].Path != "outputs[1].url" {
+		t.Errorf("expected an error opening outputs[1].url, got %v", err)
+	}
+	if GetOutput(url) != nil {
+		t.Errorf("expected %v not to be registered", url)
+	}
+}
+
+func TestValidateFormatFields(t *testing.T) {
+	err := ConfigureE(&config.Config{
+		DefaultFormat: "{{.Msg}}",
+		Outputs: []config.Output{
+			{URL: "file:///dev/stdout", Format: "{{.Date}} {{.Mesage}}"},
+			{URL: "file:///dev/stderr", Format: "{{.Date}} {{.Message}}"},
+		},
+	})
+	errs, ok := err.(config.Errors)
+	if !ok {
+		t.Fatalf("expected config.Errors, got %#v", err)
+	}
+	if len(errs) != 2 || errs[0].Path != "defaultformat" || errs[1].Path != "outputs[0].format" {
+		t.Errorf("expected errors of defaultformat and outputs[0].format, got %v", errs)
+	}
+}
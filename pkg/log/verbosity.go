@@ -0,0 +1,97 @@
+package log
+
+import (
+	"sync"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+// wantsSetter is implemented by outputs that expose their wanted levels.
+type wantsSetter interface {
+	WantedLevels() []level.Level
+	SetWants([]level.Level)
+}
+
+// verbosity holds the wanted levels of loggers and outputs from before the
+// verbosity has been raised.
+var verbosity = struct {
+	raised  bool
+	loggers map[*Logger][]level.Level
+	outputs map[wantsSetter][]level.Level
+	mu      *sync.Mutex
+}{mu: &sync.Mutex{}}
+
+// RaiseVerbosity adds DEBUG to the wanted levels of all registered loggers
+// and of the outputs that accept INFO or NOTICE, so that debug entries are
+// not written to outputs for warnings and errors, such as STDERR. Their
+// configured levels are kept until RestoreVerbosity is called. It returns
+// false if the verbosity has already been raised.
+func RaiseVerbosity() bool {
+	verbosity.mu.Lock()
+	defer verbosity.mu.Unlock()
+	if verbosity.raised {
+		return false
+	}
+	verbosity.raised = true
+	verbosity.loggers = map[*Logger][]level.Level{}
+	verbosity.outputs = map[wantsSetter][]level.Level{}
+	for _, logger := range Loggers() {
+		wants := logger.WantedLevels()
+		verbosity.loggers[logger] = wants
+		if wants != nil {
+			logger.SetWants(withDebug(wants))
+		}
+	}
+	for _, output := range Outputs() {
+		if o, ok := output.(wantsSetter); ok {
+			wants := o.WantedLevels()
+			verbosity.outputs[o] = wants
+			if acceptsInfo(wants) {
+				o.SetWants(withDebug(wants))
+			}
+		}
+	}
+	return true
+}
+
+// RestoreVerbosity restores the wanted levels of all loggers and outputs to
+// what they have been before RaiseVerbosity was called. It returns false if
+// the verbosity has not been raised.
+func RestoreVerbosity() bool {
+	verbosity.mu.Lock()
+	defer verbosity.mu.Unlock()
+	if !verbosity.raised {
+		return false
+	}
+	for logger, wants := range verbosity.loggers {
+		logger.SetWants(wants)
+	}
+	for output, wants := range verbosity.outputs {
+		output.SetWants(wants)
+	}
+	verbosity.raised = false
+	verbosity.loggers = nil
+	verbosity.outputs = nil
+	return true
+}
+
+// withDebug returns the given levels with DEBUG added.
+func withDebug(levels []level.Level) []level.Level {
+	for i := range levels {
+		if levels[i] == level.DEBUG {
+			return levels
+		}
+	}
+	return append(append([]level.Level{}, levels...), level.DEBUG)
+}
+
+// acceptsInfo returns true if the given levels include INFO or NOTICE, the
+// levels next to DEBUG.
+func acceptsInfo(levels []level.Level) bool {
+	for i := range levels {
+		if levels[i] == level.INFO || levels[i] == level.NOTICE {
+			return true
+		}
+	}
+	return false
+}
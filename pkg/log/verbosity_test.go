@@ -0,0 +1,76 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/octogo/log/pkg/level"
+)
+
+func TestVerbositySignals(t *testing.T) {
+	logger := NewLogger("TEST-VERBOSITY", []level.Level{level.ERROR, level.WARNING})
+	stop := HandleSignals()
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitFor(t, func() bool { return logger.Wants(level.DEBUG) })
+	if RaiseVerbosity() {
+		t.Errorf("expected %v, got %v", false, true)
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	waitFor(t, func() bool { return !logger.Wants(level.DEBUG) })
+	wants := logger.WantedLevels()
+	if len(wants) != 2 || wants[0] != level.ERROR || wants[1] != level.WARNING {
+		t.Errorf("expected %v, got %v", []level.Level{level.ERROR, level.WARNING}, wants)
+	}
+}
+
+func TestRaiseVerbosityDefaultOutputs(t *testing.T) {
+	stdout, err := ioutil.TempFile("", "octolog-stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(stdout.Name())
+	stderr, err := ioutil.TempFile("", "octolog-stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(stderr.Name())
+	NewFileOutput(stdout, []level.Level{level.INFO, level.NOTICE}, "{{.Message}}")
+	defer UnregisterOutput("file://" + stdout.Name())
+	NewFileOutput(stderr, []level.Level{level.WARNING, level.ERROR}, "{{.Message}}")
+	defer UnregisterOutput("file://" + stderr.Name())
+	logger := NewLogger("TEST-VERBOSITY-DEFAULT", nil, "file://"+stdout.Name(), "file://"+stderr.Name())
+	defer UnregisterLogger(logger.Name)
+
+	logger.Debug("quiet")
+	RaiseVerbosity()
+	logger.Debug("verbose")
+	RestoreVerbosity()
+	logger.Debug("quiet again")
+	for f, expected := range map[*os.File]string{stdout: "verbose", stderr: ""} {
+		b, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(b)); got != expected {
+			t.Errorf("expected %q in %s, got %q", expected, f.Name(), got)
+		}
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		if condition() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("timed out")
+}
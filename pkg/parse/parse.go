@@ -0,0 +1,254 @@
+// Package parse compiles octolog log-formats into parsers that recover
+// entries from the lines written in that format.
+//
+//	p, err := parse.Compile(log.DefaultLogFormat)
+//	if err != nil {
+//		return err
+//	}
+//	record, err := p.Decode("2019/10/10 10:10:10 main INFO hello world")
+//
+// Color codes are ignored. Values that are not written by the format remain
+// empty in the decoded records.
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/octogo/log/pkg/log"
+)
+
+// ErrNoMatch is returned for lines that do not match the format of a Parser.
+var ErrNoMatch = errors.New("line does not match the log-format")
+
+// fieldPatterns maps the fields of an Entry to the patterns matching their
+// values. Fields mapped to the empty string are not written in any line.
+var fieldPatterns = map[string]string{
+	"Date":       `\d{4}/\d{2}/\d{2}`,
+	"Time":       `\d{2}:\d{2}:\d{2}`,
+	"Milli":      `\.\d{3}`,
+	"Nano":       `\.\d{6}`,
+	"PID":        `\d+`,
+	"PPID":       `\d+`,
+	"GID":        `\d+`,
+	"LID":        `\d+`,
+	"Logger":     `\S*`,
+	"Level":      `\S+`,
+	"Func":       `\S*`,
+	"File":       `\S*`,
+	"Line":       `\d+`,
+	"Message":    `.*`,
+	"Err":        `.*`,
+	"ErrorStack": `.*`,
+	"ErrorChain": `.*`,
+	"Fields":     `.*`,
+	"Stack":      `.*`,
+	"Color":      "",
+	"BoldColor":  "",
+	"NoColor":    "",
+}
+
+// colorCodes matches ANSII color codes.
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Parser recovers entries from the lines written in a log-format.
+// It implements log.Decoder.
+type Parser struct {
+	format string
+	re     *regexp.Regexp
+	fields []string
+}
+
+// Compile returns a Parser for the given log-format. Formats must consist of
+// text and plain fields of Entry, such as {{.Date}} or {{.Message}}.
+func Compile(format string) (*Parser, error) {
+	tmpl, err := template.New("octolog/entry").Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	p := &Parser{format: format}
+	pattern := new(strings.Builder)
+	pattern.WriteString("^")
+	nodes := tmpl.Tree.Root.Nodes
+	for i, node := range nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			pattern.WriteString(regexp.QuoteMeta(string(n.Text)))
+		case *parse.ActionNode:
+			field, err := actionField(n)
+			if err != nil {
+				return nil, err
+			}
+			expr := fieldPatterns[field]
+			if expr == "" {
+				continue
+			}
+			if expr == ".*" && i < len(nodes)-1 {
+				expr = ".*?"
+			}
+			pattern.WriteString("(" + expr + ")")
+			p.fields = append(p.fields, field)
+		default:
+			return nil, fmt.Errorf("unsupported action in log-format: %s", node)
+		}
+	}
+	pattern.WriteString("$")
+	if p.re, err = regexp.Compile(pattern.String()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// actionField returns the name of the Entry field written by the given
+// action.
+func actionField(n *parse.ActionNode) (string, error) {
+	if n.Pipe == nil || len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) != 1 {
+		return "", fmt.Errorf("unsupported action in log-format: %s", n)
+	}
+	field, ok := n.Pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok || len(field.Ident) != 1 {
+		return "", fmt.Errorf("unsupported action in log-format: %s", n)
+	}
+	if _, ok := fieldPatterns[field.Ident[0]]; !ok {
+		return "", fmt.Errorf("undefined field in log-format: %s", n)
+	}
+	return field.Ident[0], nil
+}
+
+// Format returns the log-format of this Parser.
+func (p *Parser) Format() string {
+	return p.format
+}
+
+// Decode recovers the entry written in the given line.
+func (p *Parser) Decode(line string) (*log.Record, error) {
+	match := p.re.FindStringSubmatch(colorCodes.ReplaceAllString(line, ""))
+	if match == nil {
+		return nil, ErrNoMatch
+	}
+	r := &log.Record{}
+	var date, clock, fraction string
+	for i, field := range p.fields {
+		value := match[i+1]
+		switch field {
+		case "Date":
+			date = value
+		case "Time":
+			clock = value
+		case "Milli", "Nano":
+			fraction = value
+		case "PID":
+			r.PID, _ = strconv.Atoi(value)
+		case "PPID":
+			r.PPID, _ = strconv.Atoi(value)
+		case "GID":
+			r.GID, _ = strconv.ParseUint(value, 10, 64)
+		case "LID":
+			r.LID, _ = strconv.ParseUint(value, 10, 64)
+		case "Logger":
+			r.Logger = value
+		case "Level":
+			r.Level = value
+		case "Func":
+			r.Func = value
+		case "File":
+			r.File = value
+		case "Line":
+			r.Line, _ = strconv.Atoi(value)
+		case "Message":
+			r.Message = value
+		case "Err":
+			if value != "" {
+				r.ErrorChain = []log.ErrorInfo{{Message: value}}
+			}
+		case "ErrorStack":
+			r.ErrorStack = value
+		case "Stack":
+			r.Stack = value
+		}
+	}
+	r.Time = parseTime(date, clock, fraction)
+	return r, nil
+}
+
+// parseTime returns the time written by {{.Date}}, {{.Time}} and {{.Milli}}
+// or {{.Nano}} in the local time zone.
+func parseTime(date, clock, fraction string) time.Time {
+	var layout, value []string
+	if date != "" {
+		layout, value = append(layout, "2006/01/02"), append(value, date)
+	}
+	if clock != "" {
+		clockLayout := "15:04:05"
+		if fraction != "" {
+			clockLayout += "." + strings.Repeat("0", len(fraction)-1)
+		}
+		layout, value = append(layout, clockLayout), append(value, clock+fraction)
+	}
+	if len(layout) == 0 {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(strings.Join(layout, " "), strings.Join(value, " "), time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// detailPattern matches the lines of error details following an entry.
+var detailPattern = regexp.MustCompile(`^\t(?:(\S+): (.*)|([^\s=]+)=(.*))$`)
+
+// DecodeDetails restores the details written on the lines following an entry
+// in text format into the given record: the types and messages of a logged
+// error, its fields, the stack trace of the error and finally the stack trace
+// of the entry, whose functions are written as calls, e.g. main.main().
+func DecodeDetails(r *log.Record, lines []string) {
+	var chain []log.ErrorInfo
+	for len(lines) > 0 {
+		match := detailPattern.FindStringSubmatch(lines[0])
+		if match == nil {
+			break
+		}
+		if match[1] != "" && r.Fields == nil {
+			chain = append(chain, log.ErrorInfo{Type: match[1], Message: match[2]})
+		} else if match[3] != "" {
+			if r.Fields == nil {
+				r.Fields = map[string]interface{}{}
+			}
+			r.Fields[match[3]] = match[4]
+		} else {
+			break
+		}
+		lines = lines[1:]
+	}
+	if len(chain) > 0 {
+		r.ErrorChain = chain
+	}
+	if len(chain) > 0 {
+		n := stackStart(lines)
+		if n > 0 {
+			r.ErrorStack = strings.Join(lines[:n], "\n")
+		}
+		lines = lines[n:]
+	}
+	if len(lines) > 0 {
+		r.Stack = strings.Join(lines, "\n")
+	}
+}
+
+// stackStart returns the index of the first line of the stack trace of an
+// entry within the given lines, or their length if there is none.
+func stackStart(lines []string) int {
+	for i := range lines {
+		if !strings.HasPrefix(lines[i], "\t") && strings.HasSuffix(lines[i], "()") {
+			return i
+		}
+	}
+	return len(lines)
+}
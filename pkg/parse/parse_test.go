@@ -0,0 +1,120 @@
+package parse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/octogo/log/pkg/log"
+)
+
+func TestDefaultLogFormat(t *testing.T) {
+	p, err := Compile(log.DefaultLogFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := p.Decode("2019/10/10 10:10:10 \x1b[1;31mmain ERROR\x1b[0m \x1b[31mdisk full: /var\x1b[0m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := log.Record{
+		Time:    time.Date(2019, 10, 10, 10, 10, 10, 0, time.Local),
+		Logger:  "main",
+		Level:   "ERROR",
+		Message: "disk full: /var",
+	}
+	if !r.Time.Equal(expected.Time) || r.Logger != expected.Logger || r.Level != expected.Level || r.Message != expected.Message {
+		t.Errorf("expected %+v, got %+v", expected, r)
+	}
+	if _, err := p.Decode("not an entry"); err != ErrNoMatch {
+		t.Errorf("expected %v, got %v", ErrNoMatch, err)
+	}
+}
+
+func TestDefaultDebugFormat(t *testing.T) {
+	p, err := Compile(log.DefaultDebugFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := p.Decode("2019/10/10 10:10:10.123456 7|main|3 a message with spaces main.main /src/main.go:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Time.Nanosecond() != 123456000 || r.GID != 7 || r.LID != 3 || r.Message != "a message with spaces" ||
+		r.Func != "main.main" || r.File != "/src/main.go" || r.Line != 42 {
+		t.Errorf("unexpected %+v", r)
+	}
+}
+
+func TestCompileUnsupported(t *testing.T) {
+	for _, format := range []string{
+		"{{.Message",
+		"{{.Unknown}}",
+		"{{if .Err}}{{.Err}}{{end}}",
+		"{{.Message | printf \"%q\"}}",
+	} {
+		if _, err := Compile(format); err == nil {
+			t.Errorf("expected an error for %q", format)
+		}
+	}
+}
+
+func TestDecodeDetails(t *testing.T) {
+	r := &log.Record{Message: "request failed"}
+	DecodeDetails(r, []string{
+		"\tmain.wrappingError: outer: inner",
+		"\t*errors.errorString: inner",
+		"\tattempt=3",
+		"\tuser=bob",
+		"main.main()",
+		"\t/src/main.go:42",
+	})
+	if len(r.ErrorChain) != 2 || r.ErrorChain[0].Type != "main.wrappingError" || r.ErrorChain[1].Message != "inner" {
+		t.Errorf("unexpected error chain %+v", r.ErrorChain)
+	}
+	if r.Fields["attempt"] != "3" || r.Fields["user"] != "bob" {
+		t.Errorf("unexpected fields %+v", r.Fields)
+	}
+	if expected := "main.main()\n\t/src/main.go:42"; r.Stack != expected {
+		t.Errorf("expected %q, got %q", expected, r.Stack)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	p, err := Compile(log.DefaultLogFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := log.NewEncoder(log.DefaultLogFormat)
+	for _, expected := range []log.Record{
+		{Logger: "main", Level: "INFO", Message: `said "hello" & <goodbye>`},
+		{Logger: "main", Level: "INFO", Message: "a < b && c > 'd'", Fields: map[string]interface{}{"query": `"x" & <y>`}},
+		{
+			Logger:  "main",
+			Level:   "ERROR",
+			Message: "request failed: <nil> & \"timeout\"",
+			ErrorChain: []log.ErrorInfo{
+				{Type: "*errors.withStack", Message: "request failed: <nil> & \"timeout\""},
+				{Type: "*errors.errorString", Message: "<nil> & \"timeout\""},
+			},
+			ErrorStack: "main.request\n\t/src/main.go:12\nmain.main\n\t/src/main.go:7",
+			Stack:      "main.main()\n\t/src/main.go:8",
+		},
+	} {
+		expected.Time = time.Date(2019, 10, 10, 10, 10, 10, 0, time.Local)
+		lines := strings.Split(enc.Encode(expected.Entry(), true), "\n")
+		r, err := p.Decode(lines[0])
+		if err != nil {
+			t.Fatalf("%q: %s", lines[0], err)
+		}
+		DecodeDetails(r, lines[1:])
+		if !r.Time.Equal(expected.Time) {
+			t.Errorf("expected %s, got %s", expected.Time, r.Time)
+		}
+		r.Time = expected.Time
+		if !reflect.DeepEqual(*r, expected) {
+			t.Errorf("expected %+v, got %+v", expected, *r)
+		}
+	}
+}